@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/jacklaaa89/pokeapi/internal/config"
+	"github.com/jacklaaa89/pokeapi/internal/server/rules"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// configPath the path to the configuration file, config.EnvFile is used if empty.
+var configPath string
+
+// configFormat the format the configuration is printed in.
+var configFormat string
+
+// configFlags the key of the configuration value each flag overrides.
+var configFlags = map[string]string{
+	"port":                         "server.port",
+	"log-level":                    "server.log_level",
+	"trace-exporter":               "server.trace_exporter",
+	"trusted-networks":             "server.trusted_networks",
+	"cache-control":                "server.cache_control",
+	"cache-ttl":                    "server.cache.ttl",
+	"cache-stale-while-revalidate": "server.cache.stale_while_revalidate",
+	"cache-stale-if-error":         "server.cache.stale_if_error",
+	"pokeapi-endpoint":             "pokeapi.endpoints",
+	"pokeapi-timeout":              "pokeapi.timeout",
+	"pokeapi-max-retries":          "pokeapi.max_retries",
+	"translation-endpoint":         "translation.endpoints",
+	"translation-timeout":          "translation.timeout",
+	"translation-max-retries":      "translation.max_retries",
+	"translation-methods":          "translation.methods",
+	"translators":                  "translation.translators",
+	"translation-memory":           "translation.memory",
+	"translation-rules":            "translation.rules",
+}
+
+// configCmd the parent command of the commands which inspect the configuration of the serve command.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspects the configuration of the serve command",
+	Long: "Inspects the configuration of the serve command, which is loaded from the defaults, " +
+		"a YAML, JSON or TOML file, the POKEAPI_* environment variables and the flags, in that order",
+}
+
+// configPrintCmd prints the effective configuration.
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Prints the effective configuration",
+	Long:  "Prints the effective configuration after each source is applied, secrets are masked",
+	Args:  cobra.NoArgs,
+	RunE:  configPrint,
+}
+
+// configValidateCmd validates the effective configuration.
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validates the effective configuration",
+	Long: "Validates the effective configuration after each source is applied, including the " +
+		"translation rules, without starting the server",
+	Args: cobra.NoArgs,
+	RunE: configValidate,
+}
+
+// init sets up the persistent flag bindings.
+func init() {
+	addConfigFlags(configCmd.PersistentFlags())
+	configPrintCmd.Flags().StringVar(
+		&configFormat, "format", config.FormatYAML,
+		"the format to print the configuration in, can be one of yaml, json, toml",
+	)
+
+	configCmd.AddCommand(configPrintCmd, configValidateCmd)
+}
+
+// addConfigFlags defines the flags which override the configuration, the default of each
+// flag is the default configuration so that it is documented in the help.
+func addConfigFlags(fs *pflag.FlagSet) {
+	d := config.Default()
+
+	fs.StringVar(
+		&configPath, "config", "",
+		"the path to a YAML, JSON or TOML configuration file, "+config.EnvFile+" is used if not supplied",
+	)
+	fs.Int("port", d.Server.Port, "the port to listen on")
+	fs.Int(
+		"log-level", d.Server.LogLevel,
+		"the logging level, can be one of 0 (None), 1 (Error), 2 (Warn), 3 (Info), 4 (Debug)",
+	)
+	fs.String(
+		"trace-exporter", d.Server.TraceExporter,
+		"the exporter to send trace spans to, can be one of none, stdout",
+	)
+	fs.StringSlice(
+		"trusted-networks", d.Server.TrustedNetworks,
+		"the networks, in CIDR notation, whose X-Request-ID header is honoured, i.e our gateway",
+	)
+	fs.StringArray(
+		"cache-control", d.Server.CacheControl,
+		"the Cache-Control header to send with successful responses as route=directives, can be repeated",
+	)
+	fs.Duration(
+		"cache-ttl", time.Duration(d.Server.Cache.TTL),
+		"the duration a cached pokemon response is fresh for",
+	)
+	fs.Duration(
+		"cache-stale-while-revalidate", time.Duration(d.Server.Cache.StaleWhileRevalidate),
+		"the duration a stale response is served while it is revalidated in the background",
+	)
+	fs.Duration(
+		"cache-stale-if-error", time.Duration(d.Server.Cache.StaleIfError),
+		"the duration a stale response is served when an upstream API fails",
+	)
+	fs.StringSlice(
+		"pokeapi-endpoint", d.PokeAPI.Endpoints,
+		"the endpoints of the PokeAPI to fail over between, i.e https://pokeapi.co/api/v2, the public API is used if empty",
+	)
+	fs.Duration("pokeapi-timeout", time.Duration(d.PokeAPI.Timeout), "the timeout applied to each PokeAPI request")
+	fs.Int64("pokeapi-max-retries", d.PokeAPI.MaxRetries, "the maximum amount of retries of a failed PokeAPI request")
+	fs.StringSlice(
+		"translation-endpoint", d.Translation.Endpoints,
+		"the endpoints of the fun-translations API to fail over between, the public API is used if empty",
+	)
+	fs.Duration(
+		"translation-timeout", time.Duration(d.Translation.Timeout),
+		"the timeout applied to each fun-translations request",
+	)
+	fs.Int64(
+		"translation-max-retries", d.Translation.MaxRetries,
+		"the maximum amount of retries of a failed fun-translations request",
+	)
+	fs.StringSlice(
+		"translation-methods", d.Translation.Methods,
+		"the translation methods offered by the translation API to make available, i.e pirate,minion,klingon",
+	)
+	fs.StringSlice(
+		"translators", d.Translation.Translators,
+		"the translation backends to try in order until one succeeds, can be one of funtranslations, offline",
+	)
+	fs.String(
+		"translation-memory", d.Translation.Memory,
+		"the path to the translation memory which stores each translation from fun-translations",
+	)
+	fs.String(
+		"translation-rules", d.Translation.Rules,
+		"the path to a JSON or YAML file defining the rules which choose the translation method",
+	)
+}
+
+// loadConfig loads the configuration, the flags supplied to cmd override the configuration
+// file and the environment variables.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	path := configPath
+	if path == "" {
+		path = os.Getenv(config.EnvFile)
+	}
+
+	c, err := config.Load(path, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the flags which were supplied are visited.
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		key, ok := configFlags[f.Name]
+		if !ok || err != nil {
+			return
+		}
+
+		values := []string{f.Value.String()}
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			values = s.GetSlice()
+		}
+		if sErr := c.Set(key, values...); sErr != nil {
+			err = fmt.Errorf("--%s: %w", f.Name, sErr)
+		}
+	})
+	return c, err
+}
+
+// newRules registers the translation methods in the configuration and loads the translation
+// rules, the methods must be registered before the rules which reference them are loaded.
+// nil is returned if no rules are defined so that the default rules are used.
+func newRules(c config.Translation) (*rules.Engine, error) {
+	if err := translation.RegisterAll(c.Methods...); err != nil {
+		return nil, err
+	}
+
+	if c.Rules == "" {
+		return nil, nil
+	}
+	return rules.Load(c.Rules)
+}
+
+// configPrint prints the effective configuration with the secrets masked.
+func configPrint(cmd *cobra.Command, _ []string) error {
+	c, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	return c.Write(cmd.OutOrStdout(), configFormat)
+}
+
+// configValidate validates the effective configuration and the translation rules.
+func configValidate(cmd *cobra.Command, _ []string) error {
+	c, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if _, err := newRules(c.Translation); err != nil {
+		return err
+	}
+
+	cmd.Println("configuration is valid")
+	return nil
+}
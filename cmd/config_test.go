@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/config"
+)
+
+// newConfigCmd initialises a command with the configuration flags parsed from args.
+func newConfigCmd(t *testing.T, args ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	addConfigFlags(cmd.Flags())
+	require.NoError(t, cmd.ParseFlags(args))
+
+	// configPath is bound to the flag of every command.
+	t.Cleanup(func() { configPath = "" })
+	return cmd
+}
+
+// writeConfig writes the contents to a configuration file named name.
+func writeConfig(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Args     func(t *testing.T) []string
+		Env      map[string]string
+		Expected func(t *testing.T, c *config.Config, err error)
+	}{
+		{
+			Name: "Defaults",
+			Args: func(*testing.T) []string { return nil },
+			Expected: func(t *testing.T, c *config.Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, config.Default(), c)
+			},
+		},
+		{
+			Name: "Flags",
+			Args: func(*testing.T) []string {
+				return []string{
+					"--port=8080", "--cache-ttl=1m", "--translators=offline,funtranslations",
+					"--cache-control=/pokemon/{name}=public, max-age=60", "--pokeapi-endpoint=http://localhost:8080",
+				}
+			},
+			Expected: func(t *testing.T, c *config.Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 8080, c.Server.Port)
+				assert.Equal(t, config.Duration(time.Minute), c.Server.Cache.TTL)
+				assert.Equal(t, []string{"offline", "funtranslations"}, c.Translation.Translators)
+				assert.Equal(t, []string{"/pokemon/{name}=public, max-age=60"}, c.Server.CacheControl)
+				assert.Equal(t, []string{"http://localhost:8080"}, c.PokeAPI.Endpoints)
+			},
+		},
+		{
+			Name: "Layers",
+			Args: func(t *testing.T) []string {
+				path := writeConfig(t, "config.yaml", "server:\n  port: 7070\n  log_level: 2\n  trace_exporter: stdout\n")
+				return []string{"--config=" + path, "--port=9090"}
+			},
+			Env: map[string]string{
+				"POKEAPI_SERVER_PORT":      "8080",
+				"POKEAPI_SERVER_LOG_LEVEL": "3",
+			},
+			Expected: func(t *testing.T, c *config.Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 9090, c.Server.Port)                                // flag
+				assert.Equal(t, 3, c.Server.LogLevel)                               // environment
+				assert.Equal(t, config.TraceExporterStdout, c.Server.TraceExporter) // file
+			},
+		},
+		{
+			Name: "ConfigFromEnv",
+			Args: func(t *testing.T) []string {
+				require.NoError(t, os.Setenv(config.EnvFile, writeConfig(t, "config.toml", "[server]\nport = 7070\n")))
+				t.Cleanup(func() { _ = os.Unsetenv(config.EnvFile) })
+				return nil
+			},
+			Expected: func(t *testing.T, c *config.Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 7070, c.Server.Port)
+			},
+		},
+		{
+			Name: "InvalidFile",
+			Args: func(t *testing.T) []string {
+				return []string{"--config=" + writeConfig(t, "config.json", `{"server": {"prot": 1}}`)}
+			},
+			Expected: func(t *testing.T, c *config.Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			for k, v := range tc.Env {
+				require.NoError(t, os.Setenv(k, v))
+				k := k
+				t.Cleanup(func() { _ = os.Unsetenv(k) })
+			}
+
+			c, err := loadConfig(newConfigCmd(t, tc.Args(t)...))
+			tc.Expected(t, c, err)
+		})
+	}
+}
+
+func TestConfigPrint(t *testing.T) {
+	require.NoError(t, os.Setenv("POKEAPI_TRANSLATION_API_KEY", "secret"))
+	defer os.Unsetenv("POKEAPI_TRANSLATION_API_KEY")
+
+	b := new(bytes.Buffer)
+	cmd := newConfigCmd(t, "--port=8080")
+	cmd.SetOut(b)
+	require.NoError(t, configPrint(cmd, nil))
+
+	assert.Contains(t, b.String(), "port: 8080")
+	assert.Contains(t, b.String(), "api_key: '********'")
+	assert.NotContains(t, b.String(), "secret")
+}
+
+func TestConfigValidate(t *testing.T) {
+	b := new(bytes.Buffer)
+	cmd := newConfigCmd(t)
+	cmd.SetOut(b)
+	require.NoError(t, configValidate(cmd, nil))
+	assert.Contains(t, b.String(), "configuration is valid")
+
+	cmd = newConfigCmd(t, "--port=0", "--translators=google")
+	err := configValidate(cmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.port")
+	assert.Contains(t, err.Error(), "unsupported translator: google")
+
+	cmd = newConfigCmd(t, "--translation-rules="+writeConfig(t, "rules.yaml", "rules: [{method: unknown}]"))
+	err = configValidate(cmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown")
+}
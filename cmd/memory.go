@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+	"github.com/jacklaaa89/pokeapi/internal/translation/memory"
+)
+
+// translationMethods the translation methods offered by the translation API to register
+// so that entries using them can be imported, i.e pirate.
+var translationMethods []string
+
+// memoryPath the path to the translation memory.
+var memoryPath string
+
+// memoryCmd the parent command of the commands which manage the translation memory.
+var memoryCmd = &cobra.Command{
+	Use:   "memory",
+	Short: "Manages the translation memory",
+	Long: "Manages the translation memory, which stores each translation so that the " +
+		"translation API is only called once for a given text and method",
+}
+
+// memoryImportCmd imports translations into the translation memory, i.e to pre-seed it.
+var memoryImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Imports translations into the translation memory",
+	Long: "Imports JSON encoded translations, one per line as written by export, into the " +
+		"translation memory from the file or stdin if no file is supplied",
+	Args: cobra.MaximumNArgs(1),
+	RunE: memoryImport,
+}
+
+// memoryExportCmd exports the translations in the translation memory.
+var memoryExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Exports the translations in the translation memory",
+	Long: "Exports the translations in the translation memory as JSON, one per line, to the " +
+		"file or stdout if no file is supplied",
+	Args: cobra.MaximumNArgs(1),
+	RunE: memoryExport,
+}
+
+// init sets up the persistent flag bindings.
+func init() {
+	memoryCmd.PersistentFlags().StringVar(&memoryPath, "path", "", "the path to the translation memory")
+	memoryCmd.PersistentFlags().StringSliceVar(
+		&translationMethods, "translation-methods", nil,
+		"the translation methods offered by the translation API to make available, i.e pirate,minion,klingon",
+	)
+	_ = memoryCmd.MarkPersistentFlagRequired("path")
+
+	memoryCmd.AddCommand(memoryImportCmd, memoryExportCmd)
+}
+
+// openMemory opens the translation memory, registering any additional translation methods
+// so that entries using them can be imported.
+func openMemory() (*memory.Store, error) {
+	if err := translation.RegisterAll(translationMethods...); err != nil {
+		return nil, err
+	}
+	return memory.Open(memoryPath)
+}
+
+// memoryImport imports the translations from the file in args, or stdin.
+func memoryImport(cmd *cobra.Command, args []string) (err error) {
+	s, err := openMemory()
+	if err != nil {
+		return err
+	}
+	defer closeWithErr(s, &err)
+
+	var r io.Reader = cmd.InOrStdin()
+	if len(args) > 0 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	n, err := s.Import(r)
+	cmd.Printf("imported %d translations, %d stored\n", n, s.Len())
+	return err
+}
+
+// memoryExport exports the translations to the file in args, or stdout.
+func memoryExport(cmd *cobra.Command, args []string) (err error) {
+	s, err := openMemory()
+	if err != nil {
+		return err
+	}
+	defer closeWithErr(s, &err)
+
+	if len(args) == 0 || args[0] == "-" {
+		return s.Export(cmd.OutOrStdout())
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	defer closeWithErr(f, &err)
+	return s.Export(f)
+}
+
+// closeWithErr closes c, setting err to the error from closing if err is not already set.
+func closeWithErr(c io.Closer, err *error) {
+	if cErr := c.Close(); *err == nil {
+		*err = cErr
+	}
+}
@@ -10,7 +10,7 @@ var rootCmd = &cobra.Command{
 		"the pokeapi, also allowing for translations",
 }
 
-func init() { rootCmd.AddCommand(serveCmd) }
+func init() { rootCmd.AddCommand(serveCmd, memoryCmd, configCmd) }
 
 // Root returns the root command.
 func Root() *cobra.Command { return rootCmd }
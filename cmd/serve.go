@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -12,22 +14,36 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/jacklaaa89/pokeapi/internal/api/auth"
 	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/api/metrics"
+	"github.com/jacklaaa89/pokeapi/internal/api/opts"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
+	"github.com/jacklaaa89/pokeapi/internal/config"
+	"github.com/jacklaaa89/pokeapi/internal/pokeapi"
 	"github.com/jacklaaa89/pokeapi/internal/server"
 	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+	"github.com/jacklaaa89/pokeapi/internal/translation/memory"
+	"github.com/jacklaaa89/pokeapi/internal/translation/offline"
 )
 
-// defaultPort the default port to bind to when --port is not supplied.
-const defaultPort = 5555
+// cfgTranslationAPIKey the environment variable key to
+// use to set the translation API key. the variable is read on each
+// request so the key can be rotated, no key is used if not defined.
+const cfgTranslationAPIKey = "TRANSLATION_API_KEY"
 
-// port is the defined port binding.
-var port int
+// cfgTranslationAPIKeyFile the environment variable key to use to set the path to a
+// file containing the translation API key, i.e a mounted secret. the file is re-read
+// when it changes and takes precedence over cfgTranslationAPIKey.
+const cfgTranslationAPIKeyFile = "TRANSLATION_API_KEY_FILE"
 
-// logLevel is the level of logging to apply.
-var logLevel int
+// cacheRoutes the route templates whose responses are cached.
+var cacheRoutes = []string{"/pokemon/{name}", "/pokemon/{name}/translated"}
 
 // serveCmd this is the command which initialises and starts the HTTP API
-// --port can be used to override the port in which to bind to.
+// the configuration is loaded from --config and the POKEAPI_* environment variables,
+// which each flag, i.e --port, overrides.
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Serves the API over HTTP",
@@ -36,12 +52,85 @@ var serveCmd = &cobra.Command{
 }
 
 // init sets up the persistent flag bindings.
-func init() {
-	serveCmd.PersistentFlags().IntVar(&port, "port", defaultPort, "the port to listen on")
-	serveCmd.PersistentFlags().IntVar(
-		&logLevel, "log-level", int(fmt.LevelError),
-		"the logging level, can be one of 0 (None), 1 (Error), 2 (Warn), 3 (Info), 4 (Debug)",
+func init() { addConfigFlags(serveCmd.PersistentFlags()) }
+
+// newTraceExporter initialises the trace exporter defined by name.
+func newTraceExporter(name string, w io.Writer) (trace.Exporter, error) {
+	switch name {
+	case config.TraceExporterNone, "":
+		return trace.Discard(), nil
+	case config.TraceExporterStdout:
+		return trace.JSON(w), nil
+	}
+	return nil, errors.New("unsupported trace exporter: " + name)
+}
+
+// newPokeAPI initialises the PokeAPI client, the public API is used if no endpoints are configured.
+// The options supplied, i.e the metrics registry and tracer of the server, are applied after the
+// configured options.
+func newPokeAPI(c config.Client, extra ...opts.APIOption) (*pokeapi.Client, error) {
+	o, err := c.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	o = append(o, extra...)
+	if len(c.Endpoints) == 0 {
+		return pokeapi.New(o...), nil
+	}
+	return pokeapi.NewWithEndpoint(c.Endpoints[0], o...), nil
+}
+
+// newFunTranslations initialises the fun-translations client, the public API is used if no endpoints
+// are configured. The API key is read from the configured key file, the configured key,
+// TRANSLATION_API_KEY_FILE or TRANSLATION_API_KEY, whichever is defined first. The options
+// supplied, i.e the logger, metrics registry and tracer of the server, are applied after the
+// configured options.
+func newFunTranslations(c config.Translation, extra ...opts.APIOption) (*translation.Client, error) {
+	o, err := c.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	o = append(o,
+		opts.WithCredentials(translation.Credentials(auth.Optional(auth.Chain(
+			auth.FromFile(c.APIKeyFile),
+			auth.Static(string(c.APIKey)),
+			auth.FromFile(os.Getenv(cfgTranslationAPIKeyFile)),
+			auth.FromEnv(cfgTranslationAPIKey),
+		)))),
 	)
+	o = append(o, extra...)
+
+	if len(c.Endpoints) == 0 {
+		return translation.New("", o...), nil
+	}
+	return translation.NewWithEndpoint(c.Endpoints[0], "", o...), nil
+}
+
+// newTranslator initialises a translator which tries each of the backends defined by names in order,
+// fun-translations is only called if the translation is not in the translation memory m, if supplied.
+func newTranslator(names []string, fun translation.Translator, m *memory.Store) (translation.Translator, error) {
+	if len(names) == 0 {
+		return nil, errors.New("at least one translator is required")
+	}
+
+	backends := make([]translation.Translator, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case config.TranslatorFunTranslations:
+			t := fun
+			if m != nil {
+				t = m.Translator(t)
+			}
+			backends = append(backends, t)
+		case config.TranslatorOffline:
+			backends = append(backends, offline.New())
+		default:
+			return nil, errors.New("unsupported translator: " + name)
+		}
+	}
+	return translation.Composite(backends...), nil
 }
 
 // serve initialises a HTTP server and listens for any incoming connections
@@ -49,19 +138,92 @@ func init() {
 // a signal is received.
 func serve(cmd *cobra.Command, _ []string) error {
 	log.SetOutput(cmd.OutOrStdout())
-	addr := ":" + strconv.Itoa(port)
+	c, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	e, err := newTraceExporter(c.Server.TraceExporter, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	trust, err := middleware.TrustNetworks(c.Server.TrustedNetworks...)
+	if err != nil {
+		return err
+	}
+
+	cc, err := middleware.ParseCacheControl(c.Server.CacheControl)
+	if err != nil {
+		return err
+	}
+
+	r, err := newRules(c.Translation)
+	if err != nil {
+		return err
+	}
+
+	var m *memory.Store
+	if c.Translation.Memory != "" {
+		if m, err = memory.Open(c.Translation.Memory); err != nil {
+			return err
+		}
+		defer m.Close()
+	}
+
+	// the logger is shared with the fun-translations client, which warns as the quota nears zero,
+	// the metrics registry and tracer are shared with both clients, so that their calls are served
+	// from /metrics and traced as children of the request.
+	var (
+		l   = fmt.New(fmt.Level(c.Server.LogLevel))
+		reg = metrics.NewPrometheus()
+		tr  = trace.New(e)
+	)
+
+	fun, err := newFunTranslations(c.Translation, opts.WithLogger(l), opts.WithMetrics(reg), opts.WithTracer(tr))
+	if err != nil {
+		return err
+	}
+	t, err := newTranslator(c.Translation.Translators, fun, m)
+	if err != nil {
+		return err
+	}
+
+	p, err := newPokeAPI(c.PokeAPI, opts.WithMetrics(reg), opts.WithTracer(tr))
+	if err != nil {
+		return err
+	}
+
+	addr := ":" + strconv.Itoa(c.Server.Port)
 
-	log.Printf("listening on port: %d\n", port)
+	log.Printf("listening on port: %d\n", c.Server.Port)
 
-	l := fmt.New(fmt.Level(logLevel))
 	sig := make(chan os.Signal, 2)
 	signal.Notify(sig, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	svr := &http.Server{
 		Addr: addr,
-		Handler: server.Handler(
-			middleware.WithRequestID(),
-			middleware.WithLogger(l),
-		),
+		Handler: server.New(p.Pokemon, t,
+			server.WithRules(r),
+			server.WithMethods(translation.Registered()),
+			server.WithQuota(fun.Quota),
+			server.WithLogger(l),
+			server.WithTrust(trust),
+			server.WithMetrics(reg),
+			server.WithTracer(tr),
+			server.WithMiddleware(
+				middleware.WithConditional(cc),
+				middleware.WithCache(middleware.CacheOptions{
+					TTL:                  time.Duration(c.Server.Cache.TTL),
+					StaleWhileRevalidate: time.Duration(c.Server.Cache.StaleWhileRevalidate),
+					StaleIfError:         time.Duration(c.Server.Cache.StaleIfError),
+					MaxEntries:           c.Server.Cache.MaxEntries,
+					Routes:               cacheRoutes,
+				}),
+			),
+		).Handler(),
 	}
 
 	// listen in a new go-routine so we can handle signal interrupts etc.
@@ -3,13 +3,16 @@ module github.com/jacklaaa89/pokeapi
 go 1.16
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/google/go-querystring v1.1.0
 	github.com/google/uuid v1.2.0
 	github.com/gorilla/mux v1.8.0
 	github.com/gregjones/httpcache v0.0.0-20190611155906-901d90724c79
 	github.com/microcosm-cc/bluemonday v1.0.14
 	github.com/spf13/cobra v1.1.3
+	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.7.0
 	go.uber.org/zap v1.17.0
 	golang.org/x/text v0.3.6
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -13,7 +14,10 @@ type basicAuth struct {
 
 // set implements Credentials interface
 // sets the stored username and password on the request using basic auth.
-func (b *basicAuth) set(r *http.Request) { r.SetBasicAuth(b.username, b.password) }
+func (b *basicAuth) set(_ context.Context, r *http.Request) error {
+	r.SetBasicAuth(b.username, b.password)
+	return nil
+}
 
 // BasicAuth generates a Credentials set which sets the supplied username and
 // password via basic authentication.
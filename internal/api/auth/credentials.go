@@ -5,17 +5,43 @@ import (
 	"net/http"
 )
 
+// Credentials represents a method of authenticating requests.
 type Credentials interface {
-	set(r *http.Request)
+	// set applies the credentials to the request, the context allows credentials
+	// which have to be retrieved, i.e from a token endpoint, to be cancelled.
+	set(ctx context.Context, r *http.Request) error
+}
+
+// invalidator is implemented by credentials which can be invalidated after the API
+// rejects them, so that fresh credentials are retrieved for the next request.
+type invalidator interface {
+	// invalidate invalidates the credentials applied to the rejected request
+	// reporting whether the request should be retried with fresh credentials.
+	invalidate(r *http.Request) bool
 }
 
 // NewRequest wraps the standard http.NewRequestWithContext but also applies credentials when applicable.
 func NewRequest(ctx context.Context, method, endpoint string, c Credentials) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
-	if err != nil || c == nil {
+	if err != nil {
 		return req, err
 	}
 
-	c.set(req)
-	return req, nil
+	return req, Apply(ctx, req, c)
+}
+
+// Apply applies the credentials to the request, nil credentials apply nothing.
+func Apply(ctx context.Context, r *http.Request, c Credentials) error {
+	if c == nil {
+		return nil
+	}
+	return c.set(ctx, r)
+}
+
+// Invalidate invalidates the credentials applied to a request which the API rejected as
+// unauthorised. it reports whether the request should be retried with fresh credentials,
+// which is only the case for credentials which can be refreshed.
+func Invalidate(c Credentials, r *http.Request) bool {
+	i, ok := c.(invalidator)
+	return ok && i.invalidate(r)
 }
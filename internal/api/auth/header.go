@@ -1,6 +1,9 @@
 package auth
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // fromHeader allows us to set Credentials
 // for a custom header value.
@@ -10,8 +13,9 @@ type fromHeader struct {
 
 // set implements Credentials interface.
 // sets the key value as the header key and the value as the header value.
-func (f *fromHeader) set(r *http.Request) {
+func (f *fromHeader) set(_ context.Context, r *http.Request) error {
 	r.Header.Set(f.key, f.value)
+	return nil
 }
 
 // FromHeader generates credentials which allow us to set the header key and value
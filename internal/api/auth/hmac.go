@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HMACHeaders defines the headers a HMAC signature is sent in.
+type HMACHeaders struct {
+	Signature string // Signature the header to set the hex encoded signature on.
+	KeyID     string // KeyID the header to set the id of the signing key on.
+	Timestamp string // Timestamp the header to set the unix timestamp the request was signed at on.
+}
+
+// DefaultHMACHeaders the headers a HMAC signature is sent in by default.
+var DefaultHMACHeaders = HMACHeaders{
+	Signature: "X-Signature",
+	KeyID:     "X-Key-ID",
+	Timestamp: "X-Timestamp",
+}
+
+// hmacSigner the Credentials implementation which signs each request using HMAC-SHA256.
+type hmacSigner struct {
+	headers HMACHeaders // headers the headers to send the signature in.
+	keyID   string      // keyID the id of the signing key.
+	secret  []byte      // secret the signing key.
+	signed  []string    // signed the canonical names of the request headers included in the signature.
+}
+
+// set implements Credentials interface.
+// signs the request with a fresh timestamp, this is called for every attempt so a
+// retried request is never sent with a stale signature.
+func (h *hmacSigner) set(_ context.Context, r *http.Request) error {
+	ts := strconv.FormatInt(now().Unix(), 10)
+	r.Header.Set(h.headers.Timestamp, ts)
+	r.Header.Set(h.headers.KeyID, h.keyID)
+
+	s, err := h.canonical(r, ts)
+	if err != nil {
+		return err
+	}
+
+	m := hmac.New(sha256.New, h.secret)
+	m.Write([]byte(s))
+	r.Header.Set(h.headers.Signature, hex.EncodeToString(m.Sum(nil)))
+	return nil
+}
+
+// canonical generates the string which is signed, made up of the following lines:
+//
+// - the HTTP method.
+// - the escaped path.
+// - the query string, sorted by key then value.
+// - each signed header as name:value, in the order they were defined.
+// - the hex encoded SHA-256 hash of the body.
+// - the unix timestamp the request was signed at.
+func (h *hmacSigner) canonical(r *http.Request, ts string) (string, error) {
+	path := r.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	lines := []string{r.Method, path, canonicalQuery(r.URL.Query())}
+	for _, name := range h.signed {
+		lines = append(lines, strings.ToLower(name)+":"+strings.Join(r.Header.Values(name), ","))
+	}
+
+	sum, err := bodyHash(r)
+	if err != nil {
+		return "", err
+	}
+
+	lines = append(lines, sum, ts)
+	return strings.Join(lines, "\n"), nil
+}
+
+// canonicalQuery encodes the query string sorted by key then value.
+func canonicalQuery(v url.Values) string {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(v))
+	for _, k := range keys {
+		values := append([]string(nil), v[k]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, url.QueryEscape(k)+"="+url.QueryEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// bodyHash generates the hex encoded SHA-256 hash of the request body without consuming it.
+func bodyHash(r *http.Request) (string, error) {
+	// a body which cannot be re-read is buffered so that it can be hashed and still sent.
+	if r.GetBody == nil && r.Body != nil && r.Body != http.NoBody {
+		d, err := io.ReadAll(r.Body)
+		if err != nil {
+			return "", err
+		}
+		r.Body.Close()
+		r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(d)), nil }
+		r.Body, _ = r.GetBody()
+	}
+
+	hash := sha256.New()
+	if r.GetBody != nil {
+		b, err := r.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer b.Close()
+
+		if _, err := io.Copy(hash, b); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// HMAC generates credentials which sign each request with HMAC-SHA256 using the supplied
+// key, sending the signature in the default headers. signed defines the request headers
+// which are included in the signature, i.e X-Request-ID.
+func HMAC(keyID, secret string, signed ...string) Credentials {
+	return HMACWithHeaders(DefaultHMACHeaders, keyID, secret, signed...)
+}
+
+// HMACWithHeaders generates credentials which sign each request with HMAC-SHA256 using the
+// supplied key, sending the signature in the supplied headers. Any header which is not defined
+// in h uses the default header.
+func HMACWithHeaders(h HMACHeaders, keyID, secret string, signed ...string) Credentials {
+	if keyID == "" || secret == "" {
+		return nil
+	}
+
+	if h.Signature == "" {
+		h.Signature = DefaultHMACHeaders.Signature
+	}
+	if h.KeyID == "" {
+		h.KeyID = DefaultHMACHeaders.KeyID
+	}
+	if h.Timestamp == "" {
+		h.Timestamp = DefaultHMACHeaders.Timestamp
+	}
+
+	names := make([]string, len(signed))
+	for i, n := range signed {
+		names[i] = http.CanonicalHeaderKey(n)
+	}
+
+	return &hmacSigner{headers: h, keyID: keyID, secret: []byte(secret), signed: names}
+}
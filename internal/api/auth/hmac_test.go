@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sign generates the expected signature of the canonical string.
+func sign(secret, canonical string) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(canonical))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// sha generates the hex encoded SHA-256 hash of s.
+func sha(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestHMAC(t *testing.T) {
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return time.Unix(1600000000, 0) }
+
+	tt := []struct {
+		Name     string
+		Headers  HMACHeaders
+		Method   string
+		URL      string
+		Body     string
+		Expected func(t *testing.T, req *http.Request, h HMACHeaders)
+	}{
+		{
+			Name:   "Get",
+			Method: http.MethodGet,
+			URL:    "http://localhost/pokemon%20species/ditto?b=2&a=2&a=1&c=x+y",
+			Expected: func(t *testing.T, req *http.Request, h HMACHeaders) {
+				canonical := strings.Join([]string{
+					"GET",
+					"/pokemon%20species/ditto",
+					"a=1&a=2&b=2&c=x+y",
+					"x-request-id:abc.1",
+					sha(""),
+					"1600000000",
+				}, "\n")
+
+				assert.Equal(t, "key", req.Header.Get(h.KeyID))
+				assert.Equal(t, "1600000000", req.Header.Get(h.Timestamp))
+				assert.Equal(t, sign("secret", canonical), req.Header.Get(h.Signature))
+			},
+		},
+		{
+			Name:    "PostWithCustomHeaders",
+			Headers: HMACHeaders{Signature: "X-Sig"},
+			Method:  http.MethodPost,
+			URL:     "http://localhost",
+			Body:    `{"text":"hello"}`,
+			Expected: func(t *testing.T, req *http.Request, h HMACHeaders) {
+				canonical := strings.Join([]string{
+					"POST", "/", "", "x-request-id:abc.1", sha(`{"text":"hello"}`), "1600000000",
+				}, "\n")
+
+				assert.Equal(t, sign("secret", canonical), req.Header.Get("X-Sig"))
+				assert.Equal(t, "key", req.Header.Get(DefaultHMACHeaders.KeyID))
+
+				// the body is not consumed by signing.
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, `{"text":"hello"}`, string(b))
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			var body io.Reader
+			if tc.Body != "" {
+				body = io.NopCloser(strings.NewReader(tc.Body)) // hide the length so GetBody is not defined.
+			}
+
+			req, err := http.NewRequestWithContext(context.Background(), tc.Method, tc.URL, body)
+			require.NoError(st, err)
+			req.Header.Set("X-Request-ID", "abc.1")
+
+			h := HMACWithHeaders(tc.Headers, "key", "secret", "x-request-id")
+			require.NoError(st, Apply(context.Background(), req, h))
+
+			expected := tc.Headers
+			if expected.Signature == "" {
+				expected.Signature = DefaultHMACHeaders.Signature
+			}
+			expected.KeyID, expected.Timestamp = DefaultHMACHeaders.KeyID, DefaultHMACHeaders.Timestamp
+			tc.Expected(st, req, expected)
+		})
+	}
+}
+
+func TestHMAC_FreshTimestamp(t *testing.T) {
+	defer func(fn func() time.Time) { now = fn }(now)
+	current := time.Unix(1600000000, 0)
+	now = func() time.Time { return current }
+
+	c := HMAC("key", "secret")
+	req := newRequest(t, c)
+	first := req.Header.Get(DefaultHMACHeaders.Signature)
+
+	// signing the same request again, i.e on a retry, uses a fresh timestamp.
+	current = current.Add(time.Second)
+	require.NoError(t, Apply(context.Background(), req, c))
+	assert.Equal(t, "1600000001", req.Header.Get(DefaultHMACHeaders.Timestamp))
+	assert.NotEqual(t, first, req.Header.Get(DefaultHMACHeaders.Signature))
+
+	assert.Nil(t, HMAC("", "secret"))
+	assert.Nil(t, HMAC("key", ""))
+}
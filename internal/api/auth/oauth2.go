@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// expiryDelta the amount of time before a token expires in which it is refreshed
+	// to avoid using a token which expires in flight.
+	expiryDelta = 10 * time.Second
+	// grantClientCredentials the OAuth2 client credentials grant type.
+	grantClientCredentials = "client_credentials"
+	// tokenTypeBearer the default token type.
+	tokenTypeBearer = "Bearer"
+	// authorizationHeader the header the token is set on.
+	authorizationHeader = "Authorization"
+	// maxTokenResponse the maximum size of a token response we will read.
+	maxTokenResponse = 1 << 20
+)
+
+// now the function used to retrieve the current time, this can be overridden in tests.
+var now = time.Now
+
+// tokenClient the http client used to retrieve tokens.
+var tokenClient = &http.Client{Timeout: 30 * time.Second}
+
+// token an access token issued by the token endpoint.
+type token struct {
+	value  string    // value the access token.
+	kind   string    // kind the token type, i.e Bearer.
+	expiry time.Time // expiry the time the token expires, zero if the token has no known expiry.
+}
+
+// header the value of the Authorization header for the token.
+func (t *token) header() string { return t.kind + " " + t.value }
+
+// valid determines whether the token can be used, tokens are invalid shortly before they expire.
+func (t *token) valid() bool {
+	return t != nil && (t.expiry.IsZero() || now().Add(expiryDelta).Before(t.expiry))
+}
+
+// tokenResponse the successful response from the token endpoint.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// tokenError the error response from the token endpoint.
+type tokenError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// refresh represents a token request in flight, which concurrent requests wait on.
+type refresh struct {
+	done chan struct{} // done closed once the token request completes.
+	tok  *token        // tok the retrieved token.
+	err  error         // err the error retrieving the token.
+}
+
+// clientCredentials the Credentials implementation which retrieves access tokens from
+// a token endpoint using the OAuth2 client credentials grant.
+type clientCredentials struct {
+	tokenURL     string   // tokenURL the URL of the token endpoint.
+	clientID     string   // clientID the client id.
+	clientSecret string   // clientSecret the client secret.
+	scopes       []string // scopes the scopes to request.
+
+	mu      sync.Mutex
+	tok     *token   // tok the cached token.
+	pending *refresh // pending the token request in flight, if any.
+}
+
+// set implements Credentials interface.
+// sets the cached access token on the request, retrieving a new token if required.
+func (c *clientCredentials) set(ctx context.Context, r *http.Request) error {
+	t, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(authorizationHeader, t.header())
+	return nil
+}
+
+// invalidate implements invalidator interface.
+// discards the cached token if it is the token which was rejected, a token which
+// has already been refreshed by a concurrent request is kept.
+func (c *clientCredentials) invalidate(r *http.Request) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tok != nil && c.tok.header() == r.Header.Get(authorizationHeader) {
+		c.tok = nil
+	}
+	return true
+}
+
+// token retrieves the cached token, or retrieves a new token if the cached token has expired
+// only a single token request is made at a time, concurrent requests wait for its result.
+//
+// the token request is not bound to the context of the request which started it, so that
+// cancelling that request does not fail every request waiting on the token, each request
+// only stops waiting once its own context is done.
+func (c *clientCredentials) token(ctx context.Context) (*token, error) {
+	c.mu.Lock()
+	if c.tok.valid() {
+		t := c.tok
+		c.mu.Unlock()
+		return t, nil
+	}
+
+	p := c.pending
+	if p == nil {
+		p = &refresh{done: make(chan struct{})}
+		c.pending = p
+		go c.refresh(p)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return p.tok, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh retrieves a new token for the pending token request p, the request is bounded
+// by the timeout of the token client rather than the context of any single request.
+func (c *clientCredentials) refresh(p *refresh) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenClient.Timeout)
+	defer cancel()
+
+	p.tok, p.err = c.fetch(ctx)
+
+	c.mu.Lock()
+	c.pending = nil
+	if p.err == nil {
+		c.tok = p.tok
+	}
+	c.mu.Unlock()
+	close(p.done)
+}
+
+// fetch requests a new token from the token endpoint.
+func (c *clientCredentials) fetch(ctx context.Context) (*token, error) {
+	v := url.Values{"grant_type": {grantClientCredentials}}
+	if len(c.scopes) > 0 {
+		v.Set("scope", strings.Join(c.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	res, err := tokenClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxTokenResponse))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		var te tokenError
+		if json.Unmarshal(body, &te) == nil && te.Code != "" {
+			return nil, fmt.Errorf("oauth2: token request failed with status %d: %s %s",
+				res.StatusCode, te.Code, te.Description)
+		}
+		return nil, fmt.Errorf("oauth2: token request failed with status %d", res.StatusCode)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("oauth2: invalid token response: %w", err)
+	}
+
+	if tr.AccessToken == "" {
+		return nil, errors.New("oauth2: token response contained no access token")
+	}
+
+	t := &token{value: tr.AccessToken, kind: tokenTypeBearer}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, tokenTypeBearer) {
+		t.kind = tr.TokenType
+	}
+
+	if tr.ExpiresIn > 0 {
+		t.expiry = now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	return t, nil
+}
+
+// ClientCredentials generates credentials which retrieve access tokens from the token endpoint
+// using the OAuth2 client credentials grant and set them as an Authorization token on each request.
+//
+// tokens are cached until shortly before they expire and only a single token request is made
+// at a time. When the API rejects a token the token is invalidated and the request is retried
+// once with a fresh token.
+func ClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) Credentials {
+	if tokenURL == "" || clientID == "" {
+		return nil
+	}
+
+	return &clientCredentials{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+	}
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTokenServer generates a token endpoint which issues sequential tokens
+// with the supplied expiry, it returns the amount of tokens issued.
+func newTokenServer(t *testing.T, expiresIn int64, status int) (string, *int64) {
+	var issued int64
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.NoError(t, req.ParseForm())
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "client_credentials", req.PostForm.Get("grant_type"))
+		assert.Equal(t, "translate read", req.PostForm.Get("scope"))
+
+		id, secret, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", id)
+		assert.Equal(t, "secret", secret)
+
+		w.Header().Set("Content-Type", "application/json")
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client", "error_description": "unknown client"})
+			return
+		}
+
+		n := atomic.AddInt64(&issued, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "token-" + strconv.FormatInt(n, 10),
+			"token_type":   "bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s.URL, &issued
+}
+
+func TestClientCredentials(t *testing.T) {
+	defer func(fn func() time.Time) { now = fn }(now)
+	current := time.Now()
+	now = func() time.Time { return current }
+
+	u, issued := newTokenServer(t, 60, http.StatusOK)
+	c := ClientCredentials(u, "client", "secret", "translate", "read")
+
+	// the token is cached until shortly before it expires.
+	assert.Equal(t, "Bearer token-1", newRequest(t, c).Header.Get("Authorization"))
+	assert.Equal(t, "Bearer token-1", newRequest(t, c).Header.Get("Authorization"))
+	current = current.Add(60*time.Second - expiryDelta)
+	assert.Equal(t, "Bearer token-2", newRequest(t, c).Header.Get("Authorization"))
+	assert.EqualValues(t, 2, atomic.LoadInt64(issued))
+
+	// invalidating a token which has already been refreshed keeps the fresh token.
+	stale, _ := http.NewRequest(http.MethodGet, "/", nil)
+	stale.Header.Set("Authorization", "Bearer token-1")
+	assert.True(t, Invalidate(c, stale))
+	assert.Equal(t, "Bearer token-2", newRequest(t, c).Header.Get("Authorization"))
+
+	// invalidating the current token retrieves a new token.
+	assert.True(t, Invalidate(c, newRequest(t, c)))
+	assert.Equal(t, "Bearer token-3", newRequest(t, c).Header.Get("Authorization"))
+}
+
+func TestClientCredentials_Concurrent(t *testing.T) {
+	u, issued := newTokenServer(t, 3600, http.StatusOK)
+	c := ClientCredentials(u, "client", "secret", "translate", "read")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "Bearer token-1", newRequest(t, c).Header.Get("Authorization"))
+		}()
+	}
+	wg.Wait()
+
+	// only a single token is retrieved.
+	assert.EqualValues(t, 1, atomic.LoadInt64(issued))
+}
+
+// TestClientCredentials_Cancelled tests that cancelling the request which started a token
+// request does not fail the requests waiting on the same token.
+func TestClientCredentials_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token-1","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer s.Close()
+	c := ClientCredentials(s.URL, "client", "secret")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan error)
+	go func() {
+		_, err := NewRequest(ctx, http.MethodGet, "/path", c)
+		started <- err
+	}()
+
+	// wait for the token request to be in flight before a concurrent request waits on it.
+	require.Eventually(t, func() bool {
+		cc := c.(*clientCredentials)
+		cc.mu.Lock()
+		defer cc.mu.Unlock()
+		return cc.pending != nil
+	}, time.Second, time.Millisecond)
+
+	waiting := make(chan *http.Request)
+	go func() {
+		r, err := NewRequest(context.Background(), http.MethodGet, "/path", c)
+		assert.NoError(t, err)
+		waiting <- r
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-started, context.Canceled)
+
+	close(release)
+	r := <-waiting
+	require.NotNil(t, r)
+	assert.Equal(t, "Bearer token-1", r.Header.Get("Authorization"))
+}
+
+func TestClientCredentials_Error(t *testing.T) {
+	u, _ := newTokenServer(t, 3600, http.StatusUnauthorized)
+	c := ClientCredentials(u, "client", "secret", "translate", "read")
+
+	_, err := NewRequest(context.Background(), http.MethodGet, "/path", c)
+	assert.EqualError(t, err, "oauth2: token request failed with status 401: invalid_client unknown client")
+
+	assert.Nil(t, ClientCredentials("", "client", "secret"))
+	assert.False(t, Invalidate(BearerToken("token"), newRequest(t, nil)))
+}
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoSecret is returned from a Provider when it has no secret to provide.
+var ErrNoSecret = errors.New("auth: no secret available")
+
+// Provider provides a secret, i.e an API key, which is retrieved each time it is used
+// so that it can be rotated without restarting the process.
+type Provider interface {
+	// Retrieve retrieves the current secret, ErrNoSecret is returned if there is no secret.
+	Retrieve(ctx context.Context) (string, error)
+}
+
+// ProviderFunc a function which implements Provider.
+type ProviderFunc func(ctx context.Context) (string, error)
+
+// Retrieve implements Provider interface.
+func (f ProviderFunc) Retrieve(ctx context.Context) (string, error) { return f(ctx) }
+
+// Static provides a secret which never changes.
+func Static(secret string) Provider {
+	return ProviderFunc(func(context.Context) (string, error) {
+		if secret == "" {
+			return "", ErrNoSecret
+		}
+		return secret, nil
+	})
+}
+
+// FromEnv provides the secret stored in the environment variable key, the variable
+// is read each time the secret is retrieved.
+func FromEnv(key string) Provider {
+	return ProviderFunc(func(context.Context) (string, error) {
+		v := os.Getenv(key)
+		if v == "" {
+			return "", fmt.Errorf("%w: environment variable %s is not set", ErrNoSecret, key)
+		}
+		return v, nil
+	})
+}
+
+// fileProvider the Provider implementation which reads the secret from a file.
+type fileProvider struct {
+	path string // path the path to the file.
+
+	mu      sync.Mutex
+	secret  string    // secret the cached secret.
+	modTime time.Time // modTime the modification time of the file the secret was read from.
+	size    int64     // size the size of the file the secret was read from.
+}
+
+// Retrieve implements Provider interface.
+// the file is only re-read when it has changed since it was last read.
+func (f *fileProvider) Retrieve(context.Context) (string, error) {
+	if f.path == "" {
+		return "", ErrNoSecret
+	}
+
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s does not exist", ErrNoSecret, f.path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.secret != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.secret, nil
+	}
+
+	d, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimSpace(string(d))
+	if secret == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrNoSecret, f.path)
+	}
+
+	f.secret, f.modTime, f.size = secret, info.ModTime(), info.Size()
+	return secret, nil
+}
+
+// FromFile provides the secret stored in the file at path, surrounding whitespace is removed.
+// the file is re-read whenever it changes, i.e when a mounted Kubernetes secret is updated.
+func FromFile(path string) Provider { return &fileProvider{path: path} }
+
+// Chain provides the secret from the first provider which has a secret.
+// the error from each provider is returned if none of them has a secret.
+func Chain(p ...Provider) Provider {
+	return ProviderFunc(func(ctx context.Context) (string, error) {
+		errs := make([]string, 0, len(p))
+		for _, provider := range p {
+			if provider == nil {
+				continue
+			}
+
+			s, err := provider.Retrieve(ctx)
+			if err == nil {
+				return s, nil
+			}
+
+			if !errors.Is(err, ErrNoSecret) {
+				return "", err
+			}
+			errs = append(errs, err.Error())
+		}
+
+		if len(errs) == 0 {
+			return "", ErrNoSecret
+		}
+		return "", fmt.Errorf("%w: %s", ErrNoSecret, strings.Join(errs, ", "))
+	})
+}
+
+// Optional provides the secret from p, or an empty secret when p has no secret
+// credentials with an empty secret are not applied to the request.
+func Optional(p Provider) Provider {
+	return ProviderFunc(func(ctx context.Context) (string, error) {
+		s, err := p.Retrieve(ctx)
+		if errors.Is(err, ErrNoSecret) {
+			return "", nil
+		}
+		return s, err
+	})
+}
+
+// headerProvider allows us to set Credentials for a custom header
+// where the value is retrieved from a provider.
+type headerProvider struct {
+	key    string   // key the header key.
+	prefix string   // prefix the prefix to add to the secret, i.e Bearer.
+	p      Provider // p the provider of the header value.
+}
+
+// set implements Credentials interface.
+// sets the secret retrieved from the provider as the header value.
+func (h *headerProvider) set(ctx context.Context, r *http.Request) error {
+	s, err := h.p.Retrieve(ctx)
+	if err != nil || s == "" {
+		return err
+	}
+
+	r.Header.Set(h.key, h.prefix+s)
+	return nil
+}
+
+// FromHeaderProvider generates credentials which set the secret retrieved from
+// the provider as the header value on each request.
+func FromHeaderProvider(key string, p Provider) Credentials {
+	if key == "" || p == nil {
+		return nil
+	}
+	return &headerProvider{key: key, p: p}
+}
+
+// BearerTokenProvider sets the secret retrieved from the provider as a Authorization
+// Bearer token on each request.
+func BearerTokenProvider(p Provider) Credentials {
+	if p == nil {
+		return nil
+	}
+	return &headerProvider{key: "Authorization", prefix: "Bearer ", p: p}
+}
+
+// queryProvider allows us to set Credentials which are assigned to the
+// requests URL query string where the value is retrieved from a provider.
+type queryProvider struct {
+	key string   // key the query parameter key.
+	p   Provider // p the provider of the query parameter value.
+}
+
+// set implements Credentials interface.
+// sets the secret retrieved from the provider as the query parameter value.
+func (q *queryProvider) set(ctx context.Context, r *http.Request) error {
+	s, err := q.p.Retrieve(ctx)
+	if err != nil || s == "" {
+		return err
+	}
+	return (&fromQuery{q.key, s}).set(ctx, r)
+}
+
+// FromQueryStringProvider generates credentials where the secret retrieved from the
+// provider is assigned to the query string.
+func FromQueryStringProvider(key string, p Provider) Credentials {
+	if key == "" || p == nil {
+		return nil
+	}
+	return &queryProvider{key: key, p: p}
+}
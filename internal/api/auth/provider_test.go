@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	p := FromEnv("AUTH_TEST_SECRET")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+
+	_, err := p.Retrieve(context.Background())
+	assert.True(t, errors.Is(err, ErrNoSecret))
+
+	// the variable is read on each use.
+	for _, v := range []string{"first", "rotated"} {
+		require.NoError(t, os.Setenv("AUTH_TEST_SECRET", v))
+		s, err := p.Retrieve(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, v, s)
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	p := FromFile(path)
+
+	_, err := p.Retrieve(context.Background())
+	assert.True(t, errors.Is(err, ErrNoSecret))
+
+	require.NoError(t, os.WriteFile(path, []byte("first\n"), 0600))
+	s, err := p.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "first", s)
+
+	// the file is re-read once it changes.
+	require.NoError(t, os.WriteFile(path, []byte("rotated\n"), 0600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	s, err = p.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", s)
+
+	require.NoError(t, os.WriteFile(path, []byte(" \n"), 0600))
+	_, err = p.Retrieve(context.Background())
+	assert.True(t, errors.Is(err, ErrNoSecret))
+
+	_, err = FromFile("").Retrieve(context.Background())
+	assert.True(t, errors.Is(err, ErrNoSecret))
+}
+
+func TestChain(t *testing.T) {
+	failed := ProviderFunc(func(context.Context) (string, error) { return "", errors.New("failed") })
+
+	tt := []struct {
+		Name     string
+		Chain    Provider
+		Expected func(t *testing.T, s string, err error)
+	}{
+		{
+			Name:  "FirstWithSecret",
+			Chain: Chain(nil, FromFile(""), Static("second"), Static("third")),
+			Expected: func(t *testing.T, s string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "second", s)
+			},
+		},
+		{
+			Name:  "NoSecret",
+			Chain: Chain(FromFile(""), Static("")),
+			Expected: func(t *testing.T, _ string, err error) {
+				assert.True(t, errors.Is(err, ErrNoSecret))
+			},
+		},
+		{
+			Name:  "Error",
+			Chain: Chain(failed, Static("second")),
+			Expected: func(t *testing.T, _ string, err error) {
+				assert.EqualError(t, err, "failed")
+			},
+		},
+		{
+			Name:  "Optional",
+			Chain: Optional(Chain(FromFile(""))),
+			Expected: func(t *testing.T, s string, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, s)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			s, err := tc.Chain.Retrieve(context.Background())
+			tc.Expected(st, s, err)
+		})
+	}
+}
+
+func TestProviderCredentials(t *testing.T) {
+	req := newRequest(t, FromHeaderProvider("X-Api-Secret", Static("secret")))
+	assert.Equal(t, "secret", req.Header.Get("X-Api-Secret"))
+
+	req = newRequest(t, BearerTokenProvider(Static("token")))
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+	req = newRequest(t, FromQueryStringProvider("api_key", Static("secret")))
+	assert.Equal(t, "secret", req.URL.Query().Get("api_key"))
+
+	// an optional secret which is missing is not applied.
+	req = newRequest(t, FromHeaderProvider("X-Api-Secret", Optional(Static(""))))
+	assert.Empty(t, req.Header.Get("X-Api-Secret"))
+
+	// a missing secret fails the request.
+	_, err := NewRequest(context.Background(), "GET", "/path", FromHeaderProvider("X-Api-Secret", Static("")))
+	assert.True(t, errors.Is(err, ErrNoSecret))
+
+	assert.Nil(t, FromHeaderProvider("", Static("secret")))
+	assert.Nil(t, BearerTokenProvider(nil))
+	assert.Nil(t, FromQueryStringProvider("api_key", nil))
+}
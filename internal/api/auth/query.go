@@ -1,6 +1,9 @@
 package auth
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // fromHeader allows us to set Credentials
 // which are assigned to the requests URL query string.
@@ -11,10 +14,11 @@ type fromQuery struct {
 // set implements Credentials interface.
 // sets the key value as query parameter key and the value as the value
 // using the Encode function on url.Values also performs query escaping.
-func (f *fromQuery) set(r *http.Request) {
+func (f *fromQuery) set(_ context.Context, r *http.Request) error {
 	v := r.URL.Query()
 	v.Set(f.key, f.value)
 	r.URL.RawQuery = v.Encode()
+	return nil
 }
 
 // FromQueryString generates credentials where the key is assigned to the query string.
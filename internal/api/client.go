@@ -3,8 +3,10 @@ package api
 import (
 	"bytes"
 	"context"
+	_errors "errors"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -13,8 +15,12 @@ import (
 	"github.com/gregjones/httpcache"
 
 	"github.com/jacklaaa89/pokeapi/internal/api/auth"
+	"github.com/jacklaaa89/pokeapi/internal/api/endpoint"
 	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
 	"github.com/jacklaaa89/pokeapi/internal/api/opts"
+	"github.com/jacklaaa89/pokeapi/internal/api/redact"
+	"github.com/jacklaaa89/pokeapi/internal/api/requestid"
 )
 
 const (
@@ -35,34 +41,87 @@ type Client interface {
 
 // client this is the internal implementation of a api.Client
 type client struct {
-	endpoint string        // endpoint the baseURL to use.
-	cfg      *opts.Options // cfg the defined API options.
+	endpoints   endpoint.Pool           // endpoints the pool of base URLs to use.
+	interceptor interceptor.Interceptor // interceptor the chain of interceptors called around each call.
+	cfg         *opts.Options           // cfg the defined API options.
 }
 
 // Call performs a HTTP request.
 func (c *client) Call(ctx context.Context, method, path string, data, rcv interface{}) error {
-	requestID := uuid.New().String()
-	cfg := c.cfg
-	e := cfg.Encoder
-
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
 
 	// apply the per-request timeout if defined.
-	if cfg.Timeout > 0 {
+	if c.cfg.Timeout > 0 && ctx != nil {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
 		defer cancel()
 	}
 
-	uv, uErr := query.Values(data)
+	parent, _ := requestid.FromContext(ctx)
+	call := &interceptor.Call{
+		RequestID:       requestID(ctx),
+		ParentRequestID: parent,
+		Method:          method,
+		Path:            path,
+		Data:            data,
+	}
+
+	call.Err = c.redact(c.call(ctx, call, rcv))
+	if err := c.interceptor.AfterDecode(ctx, call); err != nil {
+		return withParent(c.redact(c.intercepted(call, err)), parent)
+	}
+
+	return withParent(c.redact(call.Err), parent)
+}
+
+// requestID generates the id to send with an attempt, the id is derived from the
+// request id assigned to the context if defined, otherwise a new id is generated.
+func requestID(ctx context.Context) string {
+	if id, ok := requestid.Derive(ctx); ok {
+		return id
+	}
+	return uuid.New().String()
+}
+
+// redact scrubs any secrets recorded on the error, i.e credentials in the query string
+// or body sample, so they are never exposed to interceptors, logs or the caller.
+func (c *client) redact(err error) error {
+	var e *errors.Error
+	if _errors.As(err, &e) && c.cfg.Redactor != nil {
+		c.cfg.Redactor.Error(e)
+	}
+	return err
+}
+
+// withParent records the parent request id on the error returned from a call.
+func withParent(err error, parent string) error {
+	var e *errors.Error
+	if _errors.As(err, &e) && parent != "" {
+		e.ParentRequestID = parent
+	}
+	return err
+}
+
+// call encodes the request, performs it and decodes the response into rcv.
+func (c *client) call(ctx context.Context, call *interceptor.Call, rcv interface{}) error {
+	cfg := c.cfg
+	e := cfg.Encoder
+	path, method, requestID := call.Path, call.Method, call.RequestID
+
+	if err := c.interceptor.BeforeEncode(ctx, call); err != nil {
+		return c.intercepted(call, err)
+	}
+
+	uv, uErr := query.Values(call.Data)
 	if uErr != nil {
 		return errors.FromSource(errors.CodeEncodingError, path, method, requestID, uErr)
 	}
 
 	path += `?` + uv.Encode()
-	req, err := auth.NewRequest(ctx, method, c.endpoint+path, cfg.Credentials)
+	// credentials are applied to each attempt as they may need to be refreshed.
+	req, err := auth.NewRequest(ctx, method, path, nil)
 	if err != nil {
 		return errors.FromSource(errors.CodeRequestError, path, method, requestID, err)
 	}
@@ -71,38 +130,99 @@ func (c *client) Call(ctx context.Context, method, path string, data, rcv interf
 	if isHTTPWriteMethod(method) {
 		req.Header.Set("Content-Type", e.ContentType())
 		var encErr error
-		rd, encErr = e.Encode(data)
+		rd, encErr = e.Encode(call.Data)
 		if encErr != nil {
 			return errors.FromRequestAndSource(req, errors.CodeEncodingError, encErr)
 		}
 	}
 
-	req.Header.Set(hostHeader, req.URL.Host)
 	req.Header.Set(acceptHeader, e.Accept())
 	req.Header.Set(acceptLanguageHeader, cfg.Language.String())
 	req.Header.Set(userAgentHeader, cfg.UserAgent)
 	req.Header.Set(errors.RequestIDHeader, requestID)
 
-	output, err := c.do(req, rd)
+	call.Request = req
+	output, err := c.do(ctx, call, rd)
 	if err != nil {
 		return err
 	}
 
+	call.Value = rcv
 	return c.decode(req, output, rcv)
 }
 
-// do performs the low-level HTTP request, this function also manages retries and most of
-// the logging made through the lifecycle of a request.
-func (c *client) do(req *http.Request, body io.Reader) (resp *http.Response, err error) {
-	c.cfg.Logger.Infof("Requesting %v %v%v\n", req.Method, req.URL.Host, req.URL.Path)
+// intercepted wraps an error returned from an interceptor, errors which are
+// already wrapped are returned as is so interceptors can control the error returned.
+func (c *client) intercepted(call *interceptor.Call, err error) error {
+	if v, ok := err.(*errors.Error); ok {
+		return v
+	}
+
+	if call.Request != nil {
+		return errors.FromRequestAndSource(call.Request, errors.CodeRequestError, err)
+	}
+
+	return errors.FromSource(errors.CodeRequestError, call.Path, call.Method, call.RequestID, err)
+}
 
+// do performs the low-level HTTP request, this function also manages retries, failing over
+// between endpoints and most of the logging made through the lifecycle of a request.
+//
+// the request is supplied with a URL relative to the endpoint, each attempt resolves
+// the URL against the endpoint selected from the pool. On a connection error or server
+// error the request immediately fails over to the next untried endpoint, only once every
+// endpoint has been tried is the attempt counted as a retry and the backoff applied.
+//
+// the interceptors are called before each attempt is sent and after each attempt completes.
+// credentials are applied to every attempt once the interceptors have been called, when the
+// API rejects credentials which can be refreshed the attempt is retried once with fresh credentials.
+func (c *client) do(ctx context.Context, call *interceptor.Call, body io.Reader) (resp *http.Response, err error) {
 	var res *http.Response
-	var requestDuration time.Duration
+	req := call.Request
 	if err = setBody(req, body); err != nil {
 		return nil, errors.FromRequestAndSource(req, errors.CodeEncodingError, err)
 	}
 
+	rel := *req.URL
+	tried := make([]string, 0, 1)
+	var base string
+	var reauthenticated bool
 	for retry := 0; ; {
+		if base == "" {
+			base = c.next(tried)
+		}
+		tried = append(tried, base)
+
+		if err = setEndpoint(req, base, &rel); err != nil {
+			return nil, errors.FromRequestAndSource(req, errors.CodeRequestError, err)
+		}
+
+		// every attempt is sent with a unique id when derived from the parent request id.
+		if call.Attempt > 0 && call.ParentRequestID != "" {
+			call.RequestID = requestID(ctx)
+			req.Header.Set(errors.RequestIDHeader, call.RequestID)
+		}
+
+		call.Attempt++
+		call.Retry, call.Endpoint = retry, base
+		call.Response, call.Duration, call.Err = nil, 0, nil
+		if err = c.interceptor.BeforeSend(ctx, call); err != nil {
+			return nil, c.intercepted(call, err)
+		}
+
+		// interceptors are free to replace the request, i.e to assign a new context.
+		req = call.Request
+
+		// credentials are applied once the interceptors have modified the request, so that a
+		// signature covers exactly the request which is sent. The interceptors are unwound
+		// with the error if the credentials cannot be applied.
+		if err = auth.Apply(req.Context(), req, c.cfg.Credentials); err != nil {
+			call.Err = errors.FromRequestAndSource(req, errors.CodeUnauthorized, err)
+			_ = c.interceptor.AfterResponse(ctx, call)
+			return nil, call.Err
+		}
+		c.cfg.Logger.Infof("Requesting %v %v%v\n", req.Method, req.URL.Host, req.URL.Path)
+
 		start := time.Now()
 		// we can safely ignore the error here, as setBody covers all of them.
 		cpy, _ := req.GetBody()
@@ -113,21 +233,56 @@ func (c *client) do(req *http.Request, body io.Reader) (resp *http.Response, err
 			err = errors.FromRequestAndSource(req, errors.CodeHTTPClientError, err)
 		}
 
-		requestDuration = time.Since(start)
-		c.cfg.Logger.Infof("Request completed in %v (retry: %v)", requestDuration, retry)
+		call.Duration = time.Since(start)
+		c.cfg.Logger.Infof("Request completed in %v (retry: %v)", call.Duration, retry)
 
 		if err == nil && res.StatusCode >= http.StatusBadRequest {
 			err = errors.FromResponse(req, res, res.Body)
 		}
 
+		err = c.redact(err)
+		call.Response, call.Err = res, err
+		if iErr := c.interceptor.AfterResponse(ctx, call); iErr != nil {
+			return nil, c.intercepted(call, iErr)
+		}
+		err = call.Err
+
+		// a cancelled or expired context says nothing about the health of the endpoint.
+		if req.Context().Err() == nil {
+			c.endpoints.Report(base, !shouldFailover(err, res))
+		}
+
+		// rejected credentials are invalidated and the attempt is retried once with fresh credentials.
+		if !reauthenticated && res != nil && res.StatusCode == http.StatusUnauthorized &&
+			auth.Invalidate(c.cfg.Credentials, req) {
+			c.cfg.Logger.Warnf("Retrying request %v %v with refreshed credentials", req.Method, rel.Path)
+			reauthenticated = true
+			tried = tried[:len(tried)-1]
+			discard(res)
+			continue
+		}
+
+		base = ""
+		if req.Context().Err() == nil && shouldFailover(err, res) {
+			if next, ok := c.endpoints.Next(tried); ok {
+				c.cfg.Logger.Warnf("Failing over request %v %v from %v to %v",
+					req.Method, rel.Path, tried[len(tried)-1], next)
+				base = next
+				discard(res)
+				continue
+			}
+		}
+
 		// If the response was okay, or an helpers that shouldn't be retried,
 		// we're done, and it's safe to leave the retry loop.
 		if !c.shouldRetry(err, res, retry) {
 			break
 		}
 
+		discard(res)
 		sleepDuration := c.sleepTime(retry)
 		retry++
+		tried = tried[:0]
 
 		c.cfg.Logger.Warnf("Initiating retry %v for request %v %v%v after sleeping %v",
 			retry, req.Method, req.URL.Host, req.URL.Path, sleepDuration)
@@ -143,6 +298,42 @@ func (c *client) do(req *http.Request, body io.Reader) (resp *http.Response, err
 	return res, nil
 }
 
+// discard drains and closes the body of a response which is not returned, so that the
+// connection can be reused.
+func discard(res *http.Response) {
+	if res == nil || res.Body == nil {
+		return
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+}
+
+// next retrieves the next endpoint to use from the pool, if every endpoint has
+// already been tried then the selection starts again from the full pool.
+func (c *client) next(tried []string) string {
+	if e, ok := c.endpoints.Next(tried); ok {
+		return e
+	}
+	e, _ := c.endpoints.Next(nil)
+	return e
+}
+
+// setEndpoint resolves the relative URL rel against the endpoint base and assigns
+// it to the request, the path of rel is appended to any path defined on base.
+func setEndpoint(req *http.Request, base string, rel *url.URL) error {
+	u, err := url.Parse(base)
+	if err != nil {
+		return err
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + rel.Path
+	u.RawPath = ""
+	u.RawQuery = rel.RawQuery
+	req.URL, req.Host = u, u.Host
+	req.Header.Set(hostHeader, u.Host)
+	return nil
+}
+
 // decode attempts to decode the response using the defined decoder
 // returning and logging any helpers if we failed to do so.
 func (c *client) decode(req *http.Request, resp *http.Response, rcv interface{}) error {
@@ -158,13 +349,13 @@ func (c *client) decode(req *http.Request, resp *http.Response, rcv interface{})
 	if err != nil {
 		// respond with encoding helpers.
 		src := err // we keep the original error so the context is not lost.
-		err = errors.FromResponse(req, resp, io.NopCloser(buf))
-		err.(*errors.Error).Code = errors.CodeEncodingError
-		err.(*errors.Error).Source = src.Error()
-		c.cfg.Logger.Errorf("Request failed with helpers: %v", err)
+		e := errors.FromResponse(req, resp, io.NopCloser(buf))
+		e.Code, e.Source, e.Err = errors.CodeEncodingError, src.Error(), src
+		c.cfg.Logger.Errorf("Request failed with helpers: %v", e)
+		return e
 	}
 
-	return err
+	return nil
 }
 
 // setBody function which sets up the body on a request
@@ -219,20 +410,38 @@ func (c *client) shouldRetry(err error, resp *http.Response, retries int) bool {
 	return err != nil
 }
 
+// shouldFailover determines whether the outcome of a request attempt means the
+// endpoint is unhealthy and the request should be attempted against another endpoint
+// this is the case when we failed to perform the HTTP request or received a server error.
+func shouldFailover(err error, resp *http.Response) bool {
+	if resp == nil {
+		return err != nil
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
 // noBody helper function which returns informs the http.Client
 // that there is no body on the request.
 func noBody() (io.ReadCloser, error) { return http.NoBody, nil }
 
 // New initialises a new API client with the supplied configuration.
+// the endpoint can be overridden with a pool of endpoints using opts.WithEndpoints.
 func New(endpoint string, o ...opts.APIOption) Client { return newClient(endpoint, opts.Apply(o...)) }
 
 // newClient generates a new client from an endpoint a set of compiled options.
-func newClient(endpoint string, c *opts.Options) Client {
+func newClient(e string, c *opts.Options) Client {
 	t := c.HTTPClient.Transport
 
 	ct := httpcache.NewTransport(httpcache.NewMemoryCache())
 	ct.Transport = t
 	c.HTTPClient.Transport = ct
 
-	return &client{endpoint: endpoint, cfg: c}
+	c.Logger = redact.Logger(c.Logger, c.Redactor)
+
+	p := c.Endpoints
+	if p == nil {
+		p = endpoint.Single(e)
+	}
+
+	return &client{endpoints: p, interceptor: interceptor.Chain(c.Interceptors...), cfg: c}
 }
@@ -2,18 +2,30 @@ package api
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	_errors "errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/jacklaaa89/pokeapi/internal/api/auth"
+	"github.com/jacklaaa89/pokeapi/internal/api/endpoint"
 	"github.com/jacklaaa89/pokeapi/internal/api/errors"
 	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
 	"github.com/jacklaaa89/pokeapi/internal/api/opts"
+	"github.com/jacklaaa89/pokeapi/internal/api/requestid"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
 )
 
 // validRequestData re-use the same structure for the request body.
@@ -50,7 +62,9 @@ func TestNew(t *testing.T) {
 	const endpoint = "http://localhost:3333"
 	c := New(endpoint)
 	assert.Implements(t, (*Client)(nil), c)
-	assert.Equal(t, endpoint, c.(*client).endpoint)
+	e, ok := c.(*client).endpoints.Next(nil)
+	assert.True(t, ok)
+	assert.Equal(t, endpoint, e)
 }
 
 // assertRequestHeaders asserts the user-agent and request id headers are valid.
@@ -278,3 +292,392 @@ func TestClient_Call(t *testing.T) {
 		})
 	}
 }
+
+// TestClient_CallWithEndpoints tests that requests fail over between the endpoints
+// in the pool on connection and server errors, and that the health of each endpoint is tracked.
+func TestClient_CallWithEndpoints(t *testing.T) {
+	// a closed server will always cause a connection error.
+	closed, closeServer := newEchoServer(t, nil)
+	closeServer()
+
+	tt := []struct {
+		Name      string
+		Endpoints func(healthy string) []string
+		Path      string
+		Retries   int64
+		Expected  func(t *testing.T, attempts int, p endpoint.Pool, err error)
+	}{
+		{
+			Name:      "FailoverOnConnectionError",
+			Endpoints: func(healthy string) []string { return []string{closed, healthy} },
+			Path:      "/200",
+			Expected: func(t *testing.T, attempts int, p endpoint.Pool, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, attempts)
+			},
+		},
+		{
+			Name:      "FailoverOnServerError",
+			Endpoints: func(healthy string) []string { return []string{healthy, closed} },
+			Path:      "/503",
+			Retries:   1,
+			Expected: func(t *testing.T, attempts int, p endpoint.Pool, err error) {
+				assert.Error(t, err)
+				assert.IsType(t, (*errors.Error)(nil), err)
+				// the error from the last endpoint attempted is returned.
+				assert.Equal(t, errors.CodeHTTPClientError, err.(*errors.Error).Code)
+				assert.Equal(t, 2, attempts) // the responding endpoint is tried once per retry.
+			},
+		},
+		{
+			Name:      "NoFailoverOnClientError",
+			Endpoints: func(healthy string) []string { return []string{healthy, closed} },
+			Path:      "/404",
+			Expected: func(t *testing.T, attempts int, p endpoint.Pool, err error) {
+				assert.Error(t, err)
+				assert.Equal(t, http.StatusNotFound, err.(*errors.Error).StatusCode)
+				assert.Equal(t, 1, attempts)
+			},
+		},
+		{
+			Name:      "UnhealthyEndpointEjected",
+			Endpoints: func(healthy string) []string { return []string{closed, healthy} },
+			Path:      "/200",
+			Expected: func(t *testing.T, attempts int, p endpoint.Pool, err error) {
+				assert.NoError(t, err)
+				e, ok := p.Next(nil)
+				assert.True(t, ok)
+				assert.NotEqual(t, closed, e) // the closed endpoint has been ejected.
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			var attempts int
+			healthy, closer := newEchoServer(st, func(_ *testing.T, n int, _ *http.Request) { attempts = n })
+			defer closer()
+
+			h := endpoint.Health{Threshold: 1, Ejection: time.Minute, MaxEjection: time.Minute}
+			p, err := endpoint.FailoverWithHealth(h, tc.Endpoints(healthy)...)
+			require.NoError(st, err)
+
+			c := New("", opts.WithEndpoints(p), opts.WithMaxNetworkRetries(tc.Retries))
+			err = c.Call(context.Background(), http.MethodGet, tc.Path, nil, nil)
+			tc.Expected(st, attempts, p, err)
+		})
+	}
+}
+
+// TestClient_CallWithInterceptors tests that the interceptors are called around
+// each stage of the call with the details of the call.
+func TestClient_CallWithInterceptors(t *testing.T) {
+	failure := _errors.New("an interceptor error")
+
+	tt := []struct {
+		Name        string
+		Path        string
+		Interceptor func(stages *[]string) interceptor.Interceptor
+		OnRequest   func(t *testing.T, attempts int, req *http.Request)
+		Expected    func(t *testing.T, stages []string, rcv *dummyResponseBody, err error)
+	}{
+		{
+			Name: "Lifecycle",
+			Path: "/200",
+			Interceptor: func(stages *[]string) interceptor.Interceptor {
+				return &interceptor.Funcs{
+					OnBeforeEncode: func(_ context.Context, c *interceptor.Call) error {
+						assert.NotEmpty(t, c.RequestID)
+						assert.Nil(t, c.Request)
+						*stages = append(*stages, "encode")
+						return nil
+					},
+					OnBeforeSend: func(_ context.Context, c *interceptor.Call) error {
+						assert.Equal(t, c.RequestID, c.Request.Header.Get(errors.RequestIDHeader))
+						c.Request.Header.Set("X-Injected", "true")
+						*stages = append(*stages, fmt.Sprintf("send:%d", c.Attempt))
+						return nil
+					},
+					OnAfterResponse: func(_ context.Context, c *interceptor.Call) error {
+						assert.Equal(t, http.StatusOK, c.Response.StatusCode)
+						assert.NotZero(t, c.Duration)
+						*stages = append(*stages, fmt.Sprintf("response:%d", c.Attempt))
+						return nil
+					},
+					OnAfterDecode: func(_ context.Context, c *interceptor.Call) error {
+						assert.NoError(t, c.Err)
+						assert.NotEmpty(t, c.Value.(*dummyResponseBody).Data)
+						*stages = append(*stages, "decode")
+						return nil
+					},
+				}
+			},
+			OnRequest: func(t *testing.T, attempts int, req *http.Request) {
+				assert.Equal(t, "true", req.Header.Get("X-Injected"))
+			},
+			Expected: func(t *testing.T, stages []string, rcv *dummyResponseBody, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"encode", "send:1", "response:1", "decode"}, stages)
+			},
+		},
+		{
+			Name: "CalledOnEachAttempt",
+			Path: "/500",
+			Interceptor: func(stages *[]string) interceptor.Interceptor {
+				return &interceptor.Funcs{
+					OnAfterResponse: func(_ context.Context, c *interceptor.Call) error {
+						assert.Error(t, c.Err)
+						*stages = append(*stages, fmt.Sprintf("response:%d:%d", c.Attempt, c.Retry))
+						return nil
+					},
+					OnAfterDecode: func(_ context.Context, c *interceptor.Call) error {
+						assert.Error(t, c.Err)
+						*stages = append(*stages, "decode")
+						return nil
+					},
+				}
+			},
+			Expected: func(t *testing.T, stages []string, rcv *dummyResponseBody, err error) {
+				assert.Error(t, err)
+				assert.Equal(t, []string{"response:1:0", "response:2:1", "response:3:2", "decode"}, stages)
+			},
+		},
+		{
+			Name: "ErrorBeforeSend",
+			Path: "/200",
+			Interceptor: func(stages *[]string) interceptor.Interceptor {
+				return &interceptor.Funcs{
+					OnBeforeSend: func(context.Context, *interceptor.Call) error { return failure },
+				}
+			},
+			OnRequest: func(t *testing.T, attempts int, req *http.Request) {
+				assert.Fail(t, "the request should not be sent")
+			},
+			Expected: func(t *testing.T, stages []string, rcv *dummyResponseBody, err error) {
+				assert.Error(t, err)
+				assert.IsType(t, (*errors.Error)(nil), err)
+				assert.Equal(t, errors.CodeRequestError, err.(*errors.Error).Code)
+				assert.Equal(t, failure.Error(), err.(*errors.Error).Source)
+			},
+		},
+		{
+			Name: "OverrideErrorAfterDecode",
+			Path: "/404",
+			Interceptor: func(stages *[]string) interceptor.Interceptor {
+				return &interceptor.Funcs{
+					OnAfterDecode: func(_ context.Context, c *interceptor.Call) error {
+						c.Err = nil
+						return nil
+					},
+				}
+			},
+			Expected: func(t *testing.T, stages []string, rcv *dummyResponseBody, err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			endpoint, closer := newEchoServer(st, tc.OnRequest)
+			defer closer()
+
+			var stages []string
+			c := New(endpoint, opts.WithInterceptors(tc.Interceptor(&stages)))
+			rcv := new(dummyResponseBody)
+			err := c.Call(context.Background(), http.MethodGet, tc.Path, nil, rcv)
+			tc.Expected(st, stages, rcv, err)
+		})
+	}
+}
+
+// TestClient_CallWithRequestID tests that the id sent with each attempt is derived
+// from the request id assigned to the context.
+func TestClient_CallWithRequestID(t *testing.T) {
+	var ids []string
+	endpoint, closer := newEchoServer(t, func(_ *testing.T, _ int, req *http.Request) {
+		ids = append(ids, req.Header.Get(errors.RequestIDHeader))
+	})
+	defer closer()
+
+	ctx := requestid.NewContext(context.Background(), "parent")
+	c := New(endpoint, opts.WithMaxNetworkRetries(1))
+
+	require.NoError(t, c.Call(ctx, http.MethodGet, "/200", nil, nil))
+	err := c.Call(ctx, http.MethodGet, "/500", nil, nil)
+	require.Error(t, err)
+
+	// each attempt is sent with a unique id, derived from the parent.
+	assert.Equal(t, []string{"parent.1", "parent.2", "parent.3"}, ids)
+	assert.Equal(t, "parent", err.(*errors.Error).ParentRequestID)
+	assert.Equal(t, "parent.3", err.(*errors.Error).RequestID)
+}
+
+// TestClient_CallWithRefreshedCredentials tests that credentials which are rejected
+// are refreshed and the request is retried once.
+func TestClient_CallWithRefreshedCredentials(t *testing.T) {
+	var issued int
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		issued++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, issued)
+	}))
+	defer tokens.Close()
+
+	tt := []struct {
+		Name     string
+		Valid    string
+		Expected func(t *testing.T, attempts []string, err error)
+	}{
+		{
+			Name:  "Refreshed",
+			Valid: "Bearer token-2",
+			Expected: func(t *testing.T, attempts []string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, attempts)
+			},
+		},
+		{
+			Name:  "RetriedOnce",
+			Valid: "",
+			Expected: func(t *testing.T, attempts []string, err error) {
+				assert.Error(t, err)
+				assert.Equal(t, errors.CodeUnauthorized, err.(*errors.Error).Code)
+				assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, attempts)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			issued = 0
+			var attempts []string
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				attempts = append(attempts, req.Header.Get("Authorization"))
+				if req.Header.Get("Authorization") != tc.Valid {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer s.Close()
+
+			c := New(s.URL, opts.WithMaxNetworkRetries(0),
+				opts.WithCredentials(auth.ClientCredentials(tokens.URL, "client", "secret")))
+			err := c.Call(context.Background(), http.MethodGet, "/", nil, nil)
+			tc.Expected(st, attempts, err)
+		})
+	}
+}
+
+// TestClient_CallSignsInterceptedRequest tests that credentials are applied once the interceptors
+// have modified the request, so that a signature covers the headers they set.
+func TestClient_CallSignsInterceptedRequest(t *testing.T) {
+	var verified bool
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tp := req.Header.Get("Traceparent")
+		assert.NotEmpty(t, tp)
+
+		sum := sha256.Sum256(nil)
+		canonical := strings.Join([]string{
+			http.MethodGet, "/", "", "traceparent:" + tp,
+			hex.EncodeToString(sum[:]), req.Header.Get(auth.DefaultHMACHeaders.Timestamp),
+		}, "\n")
+
+		m := hmac.New(sha256.New, []byte("secret"))
+		m.Write([]byte(canonical))
+		verified = hex.EncodeToString(m.Sum(nil)) == req.Header.Get(auth.DefaultHMACHeaders.Signature)
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	c := New(s.URL, opts.WithTracer(trace.New(trace.Discard())),
+		opts.WithCredentials(auth.HMAC("key", "secret", "traceparent")))
+	require.NoError(t, c.Call(context.Background(), http.MethodGet, "/", nil, nil))
+	assert.True(t, verified, "the signature does not cover the traceparent header")
+}
+
+// trackedBody a response body which records whether it was closed.
+type trackedBody struct {
+	io.ReadCloser
+	closed *int
+}
+
+// Close implements io.Closer interface.
+func (b *trackedBody) Close() error {
+	*b.closed++
+	return b.ReadCloser.Close()
+}
+
+// trackingTransport a http.RoundTripper which records the amount of response bodies
+// opened and closed.
+type trackingTransport struct {
+	opened, closed int
+}
+
+// RoundTrip implements http.RoundTripper interface.
+func (t *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.opened++
+	res.Body = &trackedBody{ReadCloser: res.Body, closed: &t.closed}
+	return res, nil
+}
+
+// TestClient_CallClosesDiscardedResponses tests that the body of every response which is
+// not returned, i.e a rejected attempt or an attempt which failed over, is closed.
+func TestClient_CallClosesDiscardedResponses(t *testing.T) {
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokens.Close()
+
+	var rejected bool
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !rejected {
+			rejected = true
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	p, err := endpoint.Failover(unavailable.URL, s.URL)
+	require.NoError(t, err)
+
+	tr := new(trackingTransport)
+	c := New("", opts.WithEndpoints(p), opts.WithMaxNetworkRetries(0),
+		opts.WithHTTPClient(&http.Client{Transport: tr}),
+		opts.WithCredentials(auth.ClientCredentials(tokens.URL, "client", "secret")))
+
+	// the call fails over from the unavailable endpoint, then is retried with refreshed credentials.
+	require.NoError(t, c.Call(context.Background(), http.MethodGet, "/", nil, new(dummyResponseBody)))
+	assert.Equal(t, 3, tr.opened)
+	assert.GreaterOrEqual(t, tr.closed, tr.opened)
+}
+
+// TestClient_CallRedactsSecrets tests that secrets are scrubbed from the errors returned from a call.
+func TestClient_CallRedactsSecrets(t *testing.T) {
+	// a closed server will always cause a connection error.
+	closed, closeServer := newEchoServer(t, nil)
+	closeServer()
+
+	c := New(closed, opts.WithMaxNetworkRetries(0), opts.WithCredentials(auth.FromQueryString("api_key", "secret")))
+	err := c.Call(context.Background(), http.MethodPost, "/200", &struct {
+		Password string `json:"password"`
+	}{"hunter2"}, nil)
+
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.Contains(t, err.(*errors.Error).Source, "api_key=%5BREDACTED%5D")
+}
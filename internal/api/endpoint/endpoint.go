@@ -0,0 +1,94 @@
+// Package endpoint allows an API client to be configured with more than one base URL.
+//
+// a Pool decides which endpoint each request attempt is sent to and passively tracks
+// the health of each endpoint from the outcome of those attempts, so that endpoints
+// which are failing can be ejected from rotation and re-admitted once they have had
+// time to recover.
+package endpoint
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Pool represents a set of endpoints in which requests can be sent to.
+type Pool interface {
+	// Next returns the next endpoint to send a request attempt to, skipping any
+	// endpoints defined in tried. false is returned if every endpoint has been tried.
+	Next(tried []string) (string, bool)
+	// Report records the outcome of a request attempt against the supplied endpoint
+	// this is used to determine the health of the endpoint for subsequent requests.
+	Report(endpoint string, healthy bool)
+}
+
+// Endpoint represents a single endpoint in a pool.
+type Endpoint struct {
+	// URL is the base URL of the endpoint.
+	URL string
+	// Weight is the relative weight of the endpoint, an endpoint with a weight
+	// of 2 receives twice as many requests as an endpoint with a weight of 1.
+	// a weight of zero is treated as 1.
+	Weight int
+}
+
+// single a Pool implementation which only ever has one endpoint.
+type single struct{ endpoint string }
+
+// Next implements Pool interface.
+// returns the endpoint as long as it has not already been tried.
+func (s *single) Next(tried []string) (string, bool) {
+	if contains(tried, s.endpoint) {
+		return "", false
+	}
+	return s.endpoint, true
+}
+
+// Report implements Pool interface.
+// there is nothing to fail over to, so the health of the endpoint is not tracked.
+func (*single) Report(string, bool) {}
+
+// Single returns a Pool containing a single endpoint.
+func Single(endpoint string) Pool { return &single{normalise(endpoint)} }
+
+// validate validates a set of endpoints, checking that there is at least
+// one endpoint, and each endpoint is a unique, absolute URL with a positive weight.
+func validate(endpoints []Endpoint) error {
+	if len(endpoints) == 0 {
+		return errors.New("at least one endpoint is required")
+	}
+
+	seen := make(map[string]bool, len(endpoints))
+	for _, e := range endpoints {
+		u, err := url.Parse(e.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("endpoint must be an absolute URL: " + e.URL)
+		}
+
+		if e.Weight < 0 {
+			return errors.New("endpoint weight cannot be negative: " + e.URL)
+		}
+
+		n := normalise(e.URL)
+		if seen[n] {
+			return errors.New("endpoint is defined more than once: " + e.URL)
+		}
+		seen[n] = true
+	}
+
+	return nil
+}
+
+// normalise removes any trailing slash from the endpoint as paths are
+// always appended with a leading slash.
+func normalise(endpoint string) string { return strings.TrimSuffix(endpoint, "/") }
+
+// contains reports whether the endpoint is in the supplied slice.
+func contains(s []string, endpoint string) bool {
+	for _, v := range s {
+		if v == endpoint {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package endpoint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingle(t *testing.T) {
+	p := Single(primary + "/")
+	assert.Equal(t, primary, next(t, p))
+
+	// reporting failures has no effect on a single endpoint.
+	for i := 0; i < 10; i++ {
+		p.Report(primary, false)
+	}
+	assert.Equal(t, primary, next(t, p))
+
+	_, ok := p.Next([]string{primary})
+	assert.False(t, ok)
+}
+
+func TestHealth_Validate(t *testing.T) {
+	tt := []struct {
+		Name   string
+		Health Health
+		Valid  bool
+	}{
+		{"Default", DefaultHealth(), true},
+		{"ZeroThreshold", Health{Threshold: 0, Ejection: time.Second, MaxEjection: time.Second}, false},
+		{"ZeroEjection", Health{Threshold: 1, Ejection: 0, MaxEjection: time.Second}, false},
+		{"EjectionGreaterThanMax", Health{Threshold: 1, Ejection: time.Minute, MaxEjection: time.Second}, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			err := tc.Health.validate()
+			if tc.Valid {
+				assert.NoError(st, err)
+				return
+			}
+			assert.Error(st, err)
+		})
+	}
+}
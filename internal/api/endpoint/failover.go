@@ -0,0 +1,21 @@
+package endpoint
+
+// priority a strategy which always selects the first healthy endpoint
+// in the order the endpoints were defined.
+func priority(candidates []*member) *member { return candidates[0] }
+
+// Failover returns a Pool which sends every request to the first healthy endpoint
+// in the order supplied, only failing over to the next endpoint when the ones
+// before it fail, using the default health checking policy.
+func Failover(endpoints ...string) (Pool, error) {
+	return FailoverWithHealth(DefaultHealth(), endpoints...)
+}
+
+// FailoverWithHealth the same as Failover except the supplied health checking policy is used.
+func FailoverWithHealth(h Health, endpoints ...string) (Pool, error) {
+	e := make([]Endpoint, len(endpoints))
+	for i, u := range endpoints {
+		e[i] = Endpoint{URL: u}
+	}
+	return newPool(h, priority, e)
+}
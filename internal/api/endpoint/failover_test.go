@@ -0,0 +1,138 @@
+package endpoint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	primary   = "http://primary.local"
+	secondary = "http://secondary.local"
+	tertiary  = "http://tertiary.local"
+)
+
+// withClock overrides the clock used by the package for the duration of the test
+// returning a function which moves the clock forward by the supplied duration.
+func withClock(t *testing.T) func(d time.Duration) {
+	orig := now
+	current := time.Now()
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = orig })
+	return func(d time.Duration) { current = current.Add(d) }
+}
+
+// next helper function to retrieve the next endpoint asserting one is available.
+func next(t *testing.T, p Pool, tried ...string) string {
+	e, ok := p.Next(tried)
+	require.True(t, ok)
+	return e
+}
+
+func TestFailover(t *testing.T) {
+	tt := []struct {
+		Name      string
+		Endpoints []string
+		Expected  func(t *testing.T, p Pool, err error)
+	}{
+		{
+			Name:      "Valid",
+			Endpoints: []string{primary, secondary + "/"},
+			Expected: func(t *testing.T, p Pool, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, p)
+				assert.Equal(t, primary, next(t, p))
+				assert.Equal(t, secondary, next(t, p, primary)) // the trailing slash is removed.
+			},
+		},
+		{
+			Name:      "NoEndpoints",
+			Endpoints: nil,
+			Expected: func(t *testing.T, p Pool, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, p)
+			},
+		},
+		{
+			Name:      "RelativeURL",
+			Endpoints: []string{"/api/v2"},
+			Expected: func(t *testing.T, p Pool, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, p)
+			},
+		},
+		{
+			Name:      "DuplicateEndpoint",
+			Endpoints: []string{primary, primary + "/"},
+			Expected: func(t *testing.T, p Pool, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, p)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			p, err := Failover(tc.Endpoints...)
+			tc.Expected(st, p, err)
+		})
+	}
+}
+
+func TestFailover_Next(t *testing.T) {
+	advance := withClock(t)
+	h := Health{Threshold: 2, Ejection: time.Second, MaxEjection: 3 * time.Second}
+	p, err := FailoverWithHealth(h, primary, secondary, tertiary)
+	require.NoError(t, err)
+
+	// every request goes to the primary while it is healthy.
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, primary, next(t, p))
+	}
+
+	// requests fail over in order when the previous endpoints have been tried.
+	assert.Equal(t, secondary, next(t, p, primary))
+	assert.Equal(t, tertiary, next(t, p, primary, secondary))
+	_, ok := p.Next([]string{primary, secondary, tertiary})
+	assert.False(t, ok)
+
+	// the primary is ejected after two consecutive failures.
+	p.Report(primary, false)
+	assert.Equal(t, primary, next(t, p))
+	p.Report(primary, false)
+	assert.Equal(t, secondary, next(t, p))
+
+	// the primary is re-admitted after the ejection period.
+	advance(time.Second)
+	assert.Equal(t, primary, next(t, p))
+
+	// a single failure after re-admission ejects it again for double the time.
+	p.Report(primary, false)
+	assert.Equal(t, secondary, next(t, p))
+	advance(time.Second)
+	assert.Equal(t, secondary, next(t, p))
+	advance(time.Second)
+	assert.Equal(t, primary, next(t, p))
+
+	// a success resets the endpoint back to healthy.
+	p.Report(primary, true)
+	p.Report(primary, false)
+	assert.Equal(t, primary, next(t, p))
+}
+
+func TestFailover_AllEjected(t *testing.T) {
+	advance := withClock(t)
+	h := Health{Threshold: 1, Ejection: time.Second, MaxEjection: time.Minute}
+	p, err := FailoverWithHealth(h, primary, secondary)
+	require.NoError(t, err)
+
+	p.Report(secondary, false)
+	advance(500 * time.Millisecond)
+	p.Report(primary, false)
+
+	// when every endpoint is ejected the one closest to re-admission is used.
+	assert.Equal(t, secondary, next(t, p))
+	assert.Equal(t, primary, next(t, p, secondary))
+}
@@ -0,0 +1,97 @@
+package endpoint
+
+import (
+	"errors"
+	"time"
+)
+
+// now the function used to retrieve the current time, this can be overridden in tests.
+var now = time.Now
+
+const (
+	defaultThreshold    = 3                // defaultThreshold the default amount of consecutive failures before ejection.
+	defaultEjection     = 10 * time.Second // defaultEjection the default initial ejection period.
+	defaultMaxEjection  = 5 * time.Minute  // defaultMaxEjection the default upper bound of an ejection period.
+	ejectionGrowthScale = 2                // ejectionGrowthScale the multiplier applied to each subsequent ejection.
+)
+
+// Health defines the passive health checking policy applied to each endpoint in a pool.
+//
+// an endpoint is ejected from rotation once it has failed Threshold consecutive times, and
+// is re-admitted once the ejection period has passed. A re-admitted endpoint is ejected again
+// on its next failure, with the ejection period doubling each time up to MaxEjection.
+// A single success resets the endpoint back to a healthy state.
+type Health struct {
+	Threshold   int           // Threshold the amount of consecutive failures before an endpoint is ejected.
+	Ejection    time.Duration // Ejection the amount of time an endpoint is ejected for on its first ejection.
+	MaxEjection time.Duration // MaxEjection the maximum amount of time an endpoint can be ejected for.
+}
+
+// DefaultHealth returns the default health checking policy.
+func DefaultHealth() Health {
+	return Health{
+		Threshold:   defaultThreshold,
+		Ejection:    defaultEjection,
+		MaxEjection: defaultMaxEjection,
+	}
+}
+
+// validate checks the health policy is usable.
+func (h Health) validate() error {
+	if h.Threshold < 1 {
+		return errors.New("health threshold must be at least one failure")
+	}
+
+	if h.Ejection <= 0 || h.Ejection > h.MaxEjection {
+		return errors.New("ejection must be greater than zero, but no more than the max ejection")
+	}
+
+	return nil
+}
+
+// member represents an endpoint in a pool and its current health.
+type member struct {
+	url       string    // url the base URL of the endpoint.
+	weight    int       // weight the relative weight of the endpoint.
+	current   int       // current the current weight, used in weighted round-robin selection.
+	failures  int       // failures the amount of consecutive failures.
+	ejections int       // ejections the amount of consecutive ejections.
+	until     time.Time // until the time in which the endpoint is ejected until.
+}
+
+// healthy reports whether the member is in rotation at time t.
+func (m *member) healthy(t time.Time) bool { return !t.Before(m.until) }
+
+// report records the outcome of a request attempt against the member.
+func (m *member) report(h Health, healthy bool, t time.Time) {
+	if healthy {
+		m.failures, m.ejections = 0, 0
+		return
+	}
+
+	// failures reported for requests which were in-flight
+	// when the endpoint was ejected should not extend the ejection.
+	if !m.healthy(t) {
+		return
+	}
+
+	m.failures++
+	if m.failures < h.Threshold {
+		return
+	}
+
+	d := h.Ejection
+	for i := 0; i < m.ejections && d < h.MaxEjection; i++ {
+		d *= ejectionGrowthScale
+	}
+	if d > h.MaxEjection {
+		d = h.MaxEjection
+	}
+
+	m.ejections++
+	m.until = t.Add(d)
+
+	// leave the endpoint one failure away from ejection so that
+	// once it is re-admitted, a single failure ejects it again.
+	m.failures = h.Threshold - 1
+}
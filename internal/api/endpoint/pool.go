@@ -0,0 +1,92 @@
+package endpoint
+
+import (
+	"sync"
+)
+
+// strategy selects an endpoint from a set of healthy candidates.
+// candidates is never empty and is always in the order the endpoints were defined.
+type strategy func(candidates []*member) *member
+
+// pool the Pool implementation which tracks the health of each
+// endpoint and uses a strategy to select between the healthy endpoints.
+type pool struct {
+	mu       sync.Mutex
+	health   Health    // health the health checking policy.
+	members  []*member // members the endpoints in the pool.
+	strategy strategy  // strategy the function to select between healthy endpoints.
+}
+
+// Next implements Pool interface.
+// selects the next endpoint using the defined strategy from the endpoints which
+// are healthy and not yet tried. If every untried endpoint has been ejected, then
+// rather than failing the request outright, the endpoint which is closest to being
+// re-admitted is used.
+func (p *pool) Next(tried []string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := now()
+	var next *member
+	healthy := make([]*member, 0, len(p.members))
+	for _, m := range p.members {
+		if contains(tried, m.url) {
+			continue
+		}
+
+		if m.healthy(t) {
+			healthy = append(healthy, m)
+			continue
+		}
+
+		if next == nil || m.until.Before(next.until) {
+			next = m
+		}
+	}
+
+	if len(healthy) > 0 {
+		next = p.strategy(healthy)
+	}
+
+	if next == nil {
+		return "", false
+	}
+
+	return next.url, true
+}
+
+// Report implements Pool interface.
+// records the outcome against the endpoint, ignoring any unknown endpoints.
+func (p *pool) Report(endpoint string, healthy bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, m := range p.members {
+		if m.url == endpoint {
+			m.report(p.health, healthy, now())
+			return
+		}
+	}
+}
+
+// newPool validates and generates a new pool.
+func newPool(h Health, s strategy, endpoints []Endpoint) (Pool, error) {
+	if err := h.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := validate(endpoints); err != nil {
+		return nil, err
+	}
+
+	members := make([]*member, len(endpoints))
+	for i, e := range endpoints {
+		w := e.Weight
+		if w == 0 {
+			w = 1
+		}
+		members[i] = &member{url: normalise(e.URL), weight: w}
+	}
+
+	return &pool{health: h, members: members, strategy: s}, nil
+}
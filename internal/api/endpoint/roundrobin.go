@@ -0,0 +1,35 @@
+package endpoint
+
+// smoothWeighted a strategy which performs smooth weighted round-robin selection.
+//
+// on each selection every candidate has its current weight increased by its
+// configured weight, the candidate with the highest current weight is selected and
+// then has the total of all weights deducted from its current weight. This spreads
+// the selections of heavier endpoints out evenly rather than in bursts, i.e weights
+// of {a: 2, b: 1} produce a, b, a rather than a, a, b.
+func smoothWeighted(candidates []*member) *member {
+	var total int
+	var best *member
+	for _, m := range candidates {
+		m.current += m.weight
+		total += m.weight
+		if best == nil || m.current > best.current {
+			best = m
+		}
+	}
+
+	best.current -= total
+	return best
+}
+
+// WeightedRoundRobin returns a Pool which distributes requests between the healthy
+// endpoints in proportion to their weights using the default health checking policy.
+func WeightedRoundRobin(endpoints ...Endpoint) (Pool, error) {
+	return WeightedRoundRobinWithHealth(DefaultHealth(), endpoints...)
+}
+
+// WeightedRoundRobinWithHealth the same as WeightedRoundRobin except the supplied
+// health checking policy is used.
+func WeightedRoundRobinWithHealth(h Health, endpoints ...Endpoint) (Pool, error) {
+	return newPool(h, smoothWeighted, endpoints)
+}
@@ -0,0 +1,75 @@
+package endpoint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedRoundRobin(t *testing.T) {
+	tt := []struct {
+		Name      string
+		Endpoints []Endpoint
+		Expected  func(t *testing.T, p Pool, err error)
+	}{
+		{
+			Name:      "Valid",
+			Endpoints: []Endpoint{{URL: primary, Weight: 2}, {URL: secondary}},
+			Expected: func(t *testing.T, p Pool, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, p)
+			},
+		},
+		{
+			Name:      "NegativeWeight",
+			Endpoints: []Endpoint{{URL: primary, Weight: -1}},
+			Expected: func(t *testing.T, p Pool, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, p)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			p, err := WeightedRoundRobin(tc.Endpoints...)
+			tc.Expected(st, p, err)
+		})
+	}
+}
+
+func TestWeightedRoundRobin_Next(t *testing.T) {
+	advance := withClock(t)
+	h := Health{Threshold: 1, Ejection: time.Second, MaxEjection: time.Second}
+	p, err := WeightedRoundRobinWithHealth(h,
+		Endpoint{URL: primary, Weight: 3},
+		Endpoint{URL: secondary, Weight: 1},
+	)
+	require.NoError(t, err)
+
+	// the selections are smoothly distributed in proportion to the weights.
+	var selected []string
+	for i := 0; i < 8; i++ {
+		selected = append(selected, next(t, p))
+	}
+	assert.Equal(t, []string{
+		primary, primary, secondary, primary,
+		primary, primary, secondary, primary,
+	}, selected)
+
+	// an ejected endpoint receives no requests.
+	p.Report(secondary, false)
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, primary, next(t, p))
+	}
+
+	advance(time.Second)
+	counts := make(map[string]int)
+	for i := 0; i < 8; i++ {
+		counts[next(t, p)]++
+	}
+	assert.Equal(t, 6, counts[primary])
+	assert.Equal(t, 2, counts[secondary])
+}
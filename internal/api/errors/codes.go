@@ -1,7 +1,14 @@
 package errors
 
+// Code a machine-readable code which describes an error.
+//
+// Code implements error so that each code can be used as a sentinel
+// with errors.Is, i.e errors.Is(err, CodeNotFound).
 type Code string
 
+// Error implements error interface.
+func (c Code) Error() string { return string(c) }
+
 const (
 	CodeUnknownError Code = "unknown_error"
 
@@ -21,3 +28,22 @@ const (
 	CodeRequestError    Code = "request_error"
 	CodeHTTPClientError Code = "http_client_error"
 )
+
+// sentinel errors for each code, which can be used with errors.Is.
+var (
+	ErrUnknown            error = CodeUnknownError
+	ErrInvalidRequest     error = CodeInvalidRequest
+	ErrForbidden          error = CodeForbidden
+	ErrUnauthorized       error = CodeUnauthorized
+	ErrNotFound           error = CodeNotFound
+	ErrInvalidOperation   error = CodeInvalidOperation
+	ErrConflict           error = CodeConflict
+	ErrInvalidContentType error = CodeInvalidContentType
+	ErrValidation         error = CodeValidationError
+	ErrRateLimitExceeded  error = CodeRateLimitExceeded
+	ErrServer             error = CodeServerError
+	ErrServerUnavailable  error = CodeServerUnavailable
+	ErrEncoding           error = CodeEncodingError
+	ErrRequest            error = CodeRequestError
+	ErrHTTPClient         error = CodeHTTPClientError
+)
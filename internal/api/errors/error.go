@@ -1,9 +1,13 @@
 package errors
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 // RequestIDHeader the header to send the unique request id
@@ -22,22 +26,65 @@ type Error struct {
 	Resource string `json:"resource"`
 	// RequestID the uuid of the request.
 	RequestID string `json:"request_id"`
+	// ParentRequestID the id of the inbound request which caused the request, if any.
+	ParentRequestID string `json:"parent_request_id,omitempty"`
 	// Request a sample of the request body.
 	// this will be nil if there was either no body on the request
 	// or the error is not associated with performing the API request.
 	Request []byte `json:"request,omitempty"`
 	// Response a sample of the response body.
 	Response []byte `json:"response"`
-	// Source this is the underlined error if applicable.
+	// Source this is the message of the underlined error if applicable.
 	Source string `json:"source,omitempty"`
+	// Err the underlined error if applicable, this is available using errors.Unwrap.
+	Err error `json:"-"`
 }
 
-// Error implements helpers interface.
+// Error implements error interface.
+// returns a human-readable description of the error, use JSON for the structured form.
 func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(string(e.Code))
+
+	if e.Method != "" || e.Resource != "" {
+		b.WriteString(": " + strings.TrimSpace(e.Method+" "+e.Resource))
+	}
+
+	if e.StatusCode > 0 {
+		b.WriteString(" returned " + strconv.Itoa(e.StatusCode))
+	}
+
+	if e.RequestID != "" {
+		b.WriteString(" (request id: " + e.RequestID + ")")
+	}
+
+	if e.Source != "" {
+		b.WriteString(": " + e.Source)
+	}
+	return b.String()
+}
+
+// JSON returns the error encoded as JSON.
+func (e *Error) JSON() string {
 	d, _ := json.Marshal(e)
 	return string(d)
 }
 
+// Unwrap returns the underlined error, if any.
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether the error has the same code as target, this allows the error to be
+// compared against the sentinel for each code, i.e errors.Is(err, ErrNotFound).
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case Code:
+		return e.Code == t
+	case *Error:
+		return e.Code == t.Code
+	}
+	return false
+}
+
 // code default helpers handler which maps HTTP status codes
 // to an helpers code which gives a little bit more context to an helpers.
 func code(statusCode int) Code {
@@ -77,13 +124,14 @@ func FromSource(code Code, path, method, id string, src error) *Error {
 		Resource:  path,
 		RequestID: id,
 		Source:    errorMessage(src),
+		Err:       src,
 	}
 }
 
 // FromRequestAndSource generates a wrapped error from a HTTP request, overriding the code and source error.
 func FromRequestAndSource(req *http.Request, code Code, src error) *Error {
 	err := FromRequest(req)
-	err.Code, err.Source = code, errorMessage(src)
+	err.Code, err.Source, err.Err = code, errorMessage(src), src
 	return err
 }
 
@@ -142,3 +190,52 @@ func errorMessage(err error) string {
 
 	return err.Error()
 }
+
+// Is reports whether err, or any error it wraps, is an Error with the supplied code.
+func Is(err error, code Code) bool { return errors.Is(err, code) }
+
+// IsNotFound reports whether err represents a resource which could not be found.
+func IsNotFound(err error) bool { return Is(err, CodeNotFound) }
+
+// IsUnauthorized reports whether err represents a request which was not authorised.
+func IsUnauthorized(err error) bool { return Is(err, CodeUnauthorized) || Is(err, CodeForbidden) }
+
+// IsRateLimited reports whether err represents a request which exceeded a rate limit.
+func IsRateLimited(err error) bool { return Is(err, CodeRateLimitExceeded) }
+
+// IsTimeout reports whether err represents a request which timed out, either waiting
+// on the API or due to the deadline of the context.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var t interface{ Timeout() bool }
+	if errors.As(err, &t) && t.Timeout() {
+		return true
+	}
+
+	var e *Error
+	return errors.As(err, &e) &&
+		(e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusGatewayTimeout)
+}
+
+// IsRetryable reports whether err represents a transient failure, where the same
+// request may succeed if it is attempted again.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+
+	switch e.Code {
+	case CodeServerError, CodeServerUnavailable, CodeHTTPClientError:
+		return true
+	}
+
+	return e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusRequestTimeout
+}
@@ -215,7 +215,115 @@ func TestError_Error(t *testing.T) {
 		Request:    generateBodySample(1),
 		Response:   generateBodySample(1),
 	}
-	assert.Equal(t, expectedErrorJSON, err.Error())
+	assert.Equal(t, expectedErrorJSON, err.JSON())
+	assert.Equal(t, "unknown_error: GET /path returned 418 (request id: 12345)", err.Error())
+
+	err = &Error{Code: CodeHTTPClientError, Method: http.MethodGet, Resource: "/path", Source: "connection refused"}
+	assert.Equal(t, "http_client_error: GET /path: connection refused", err.Error())
+	assert.Equal(t, "encoding_error", (&Error{Code: CodeEncodingError}).Error())
+}
+
+// timeoutError an error which reports it is a timeout, i.e a net.Error.
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+func TestError_Is(t *testing.T) {
+	src := errors.New("a request error")
+	err := fmt.Errorf("wrapped: %w", FromSource(CodeNotFound, "/path", http.MethodGet, "12345", src))
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(err, CodeNotFound))
+	assert.True(t, errors.Is(err, &Error{Code: CodeNotFound}))
+	assert.False(t, errors.Is(err, ErrServer))
+
+	// the original error is kept.
+	assert.True(t, errors.Is(err, src))
+
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, src, errors.Unwrap(e))
+}
+
+func TestPredicates(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Err      error
+		Expected func(t *testing.T, err error)
+	}{
+		{
+			Name: "NotFound",
+			Err:  &Error{Code: CodeNotFound, StatusCode: http.StatusNotFound},
+			Expected: func(t *testing.T, err error) {
+				assert.True(t, IsNotFound(err))
+				assert.False(t, IsRetryable(err))
+				assert.False(t, IsTimeout(err))
+			},
+		},
+		{
+			Name: "Unauthorized",
+			Err:  &Error{Code: CodeForbidden},
+			Expected: func(t *testing.T, err error) {
+				assert.True(t, IsUnauthorized(err))
+				assert.False(t, IsNotFound(err))
+			},
+		},
+		{
+			Name: "RateLimited",
+			Err:  &Error{Code: CodeRateLimitExceeded},
+			Expected: func(t *testing.T, err error) {
+				assert.True(t, IsRateLimited(err))
+				assert.False(t, IsRetryable(err))
+			},
+		},
+		{
+			Name: "ServerUnavailable",
+			Err:  &Error{Code: CodeServerUnavailable, StatusCode: http.StatusGatewayTimeout},
+			Expected: func(t *testing.T, err error) {
+				assert.True(t, IsRetryable(err))
+				assert.True(t, IsTimeout(err))
+			},
+		},
+		{
+			Name: "NetworkTimeout",
+			Err:  FromSource(CodeHTTPClientError, "/path", http.MethodGet, "12345", timeoutError{}),
+			Expected: func(t *testing.T, err error) {
+				assert.True(t, IsRetryable(err))
+				assert.True(t, IsTimeout(err))
+			},
+		},
+		{
+			Name: "DeadlineExceeded",
+			Err:  FromSource(CodeHTTPClientError, "/path", http.MethodGet, "12345", context.DeadlineExceeded),
+			Expected: func(t *testing.T, err error) {
+				assert.True(t, IsTimeout(err))
+			},
+		},
+		{
+			Name: "Cancelled",
+			Err:  FromSource(CodeHTTPClientError, "/path", http.MethodGet, "12345", context.Canceled),
+			Expected: func(t *testing.T, err error) {
+				assert.False(t, IsRetryable(err))
+				assert.False(t, IsTimeout(err))
+			},
+		},
+		{
+			Name: "NotAnAPIError",
+			Err:  errors.New("an error"),
+			Expected: func(t *testing.T, err error) {
+				assert.False(t, IsNotFound(err))
+				assert.False(t, IsRetryable(err))
+				assert.False(t, IsTimeout(err))
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			tc.Expected(st, tc.Err)
+		})
+	}
 }
 
 func TestFromSource(t *testing.T) {
@@ -233,6 +341,7 @@ func TestFromSource(t *testing.T) {
 				assert.Equal(t, http.MethodGet, err.Method)
 				assert.Equal(t, "12345", err.RequestID)
 				assert.Equal(t, "a request error", err.Source)
+				assert.EqualError(t, err.Err, "a request error")
 			},
 		},
 		{
@@ -0,0 +1,81 @@
+package interceptor
+
+import "context"
+
+// chain an Interceptor implementation which wraps a set of interceptors.
+type chain []Interceptor
+
+// BeforeEncode implements Interceptor interface.
+// calls each interceptor in order.
+func (ch chain) BeforeEncode(ctx context.Context, c *Call) error {
+	return ch.forward(func(i Interceptor) error { return i.BeforeEncode(ctx, c) })
+}
+
+// BeforeSend implements Interceptor interface.
+// calls each interceptor in order, when an interceptor returns an error the interceptors
+// before it are unwound by calling AfterResponse in reverse order with the error assigned
+// to the call, so that anything started for the attempt, i.e a trace span, is finished.
+func (ch chain) BeforeSend(ctx context.Context, c *Call) error {
+	for idx, i := range ch {
+		err := i.BeforeSend(ctx, c)
+		if err == nil {
+			continue
+		}
+
+		c.Err = err
+		for u := idx - 1; u >= 0; u-- {
+			// the attempt has already failed, so errors from unwinding are ignored.
+			_ = ch[u].AfterResponse(ctx, c)
+		}
+		return err
+	}
+	return nil
+}
+
+// AfterResponse implements Interceptor interface.
+// calls each interceptor in reverse order.
+func (ch chain) AfterResponse(ctx context.Context, c *Call) error {
+	return ch.reverse(func(i Interceptor) error { return i.AfterResponse(ctx, c) })
+}
+
+// AfterDecode implements Interceptor interface.
+// calls each interceptor in reverse order.
+func (ch chain) AfterDecode(ctx context.Context, c *Call) error {
+	return ch.reverse(func(i Interceptor) error { return i.AfterDecode(ctx, c) })
+}
+
+// forward calls fn with each interceptor in order, stopping on the first error.
+func (ch chain) forward(fn func(i Interceptor) error) error {
+	for _, i := range ch {
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reverse calls fn with each interceptor in reverse order, stopping on the first error.
+func (ch chain) reverse(fn func(i Interceptor) error) error {
+	for idx := len(ch) - 1; idx >= 0; idx-- {
+		if err := fn(ch[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Chain combines a set of interceptors into a single Interceptor.
+//
+// the "before" stages are called in the order the interceptors are supplied and the
+// "after" stages are called in the reverse order, so the first interceptor wraps all of
+// the others, in the same fashion as HTTP middleware. nil interceptors are ignored.
+func Chain(i ...Interceptor) Interceptor {
+	ch := make(chain, 0, len(i))
+	for _, v := range i {
+		if v == nil {
+			continue
+		}
+		ch = append(ch, v)
+	}
+	return ch
+}
@@ -0,0 +1,71 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder generates an interceptor which records the name and stage
+// of each call made against it into out.
+func recorder(name string, out *[]string) Interceptor {
+	record := func(stage string) Func {
+		return func(context.Context, *Call) error {
+			*out = append(*out, name+":"+stage)
+			return nil
+		}
+	}
+	return &Funcs{
+		OnBeforeEncode:  record("encode"),
+		OnBeforeSend:    record("send"),
+		OnAfterResponse: record("response"),
+		OnAfterDecode:   record("decode"),
+	}
+}
+
+func TestChain(t *testing.T) {
+	var out []string
+	ch := Chain(recorder("a", &out), nil, recorder("b", &out))
+	ctx, c := context.Background(), &Call{}
+
+	assert.NoError(t, ch.BeforeEncode(ctx, c))
+	assert.NoError(t, ch.BeforeSend(ctx, c))
+	assert.NoError(t, ch.AfterResponse(ctx, c))
+	assert.NoError(t, ch.AfterDecode(ctx, c))
+
+	assert.Equal(t, []string{
+		"a:encode", "b:encode",
+		"a:send", "b:send",
+		"b:response", "a:response",
+		"b:decode", "a:decode",
+	}, out)
+}
+
+func TestChain_Error(t *testing.T) {
+	var out []string
+	failure := errors.New("a test error")
+	fails := &Funcs{
+		OnBeforeSend:  func(context.Context, *Call) error { return failure },
+		OnAfterDecode: func(context.Context, *Call) error { return failure },
+	}
+
+	ch := Chain(recorder("a", &out), fails, recorder("b", &out))
+	ctx, c := context.Background(), &Call{}
+
+	// the chain stops at the interceptor which returned the error, the interceptors
+	// which already sent the attempt are unwound.
+	assert.Equal(t, failure, ch.BeforeSend(ctx, c))
+	assert.Equal(t, failure, c.Err)
+	assert.Equal(t, failure, ch.AfterDecode(ctx, c))
+	assert.Equal(t, []string{"a:send", "a:response", "b:decode"}, out)
+}
+
+func TestCall_Get(t *testing.T) {
+	type key struct{}
+	c := &Call{}
+	assert.Nil(t, c.Get(key{}))
+	c.Set(key{}, "value")
+	assert.Equal(t, "value", c.Get(key{}))
+}
@@ -0,0 +1,115 @@
+// Package interceptor allows us to observe and modify the requests and responses made
+// by an API client around each stage of a call, without having to replace the http.Client.
+//
+// this is useful for cross-cutting concerns such as metrics, tracing, header injection and auditing.
+package interceptor
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Call contains the details of a single call made through an API client.
+// the same Call is supplied to each stage of the call so interceptors can
+// observe how the call progresses.
+type Call struct {
+	RequestID string      // RequestID the unique id sent with the current attempt.
+	Method    string      // Method the HTTP method.
+	Path      string      // Path the requested path, relative to the endpoint.
+	Data      interface{} // Data the request data supplied to the call.
+
+	// ParentRequestID the id of the inbound request assigned to the context supplied to
+	// the call, the id sent with each attempt is derived from it when defined.
+	ParentRequestID string
+
+	// Request the HTTP request, this is nil until the request has been encoded.
+	// interceptors can modify the request before each attempt is sent.
+	Request *http.Request
+	// Attempt the attempt number, starting from 1, this increases on every attempt
+	// including those made when failing over to another endpoint.
+	Attempt int
+	// Retry the retry the current attempt belongs to, starting from 0.
+	Retry int
+	// Endpoint the endpoint the current attempt was sent to.
+	Endpoint string
+	// Response the HTTP response to the current attempt, this is nil if the
+	// request could not be performed.
+	Response *http.Response
+	// Duration the duration of the current attempt.
+	Duration time.Duration
+
+	// Value the receiver the response was decoded into.
+	Value interface{}
+	// Err the error from the current attempt, or once the call is complete
+	// the error the call failed with. interceptors can modify this after the
+	// call has completed to change the outcome of the call.
+	Err error
+
+	values map[interface{}]interface{} // values state stored by interceptors.
+}
+
+// Set stores state against the call, this allows an interceptor to pass
+// state between the different stages of the same call.
+func (c *Call) Set(key, value interface{}) {
+	if c.values == nil {
+		c.values = make(map[interface{}]interface{})
+	}
+	c.values[key] = value
+}
+
+// Get retrieves state previously stored against the call using Set.
+func (c *Call) Get(key interface{}) interface{} { return c.values[key] }
+
+// Interceptor represents a set of functions which are called around each stage of a call.
+// returning an error from any stage fails the call with the returned error.
+type Interceptor interface {
+	// BeforeEncode is called before the request data is encoded.
+	BeforeEncode(ctx context.Context, c *Call) error
+	// BeforeSend is called before each attempt is sent. when a later interceptor fails
+	// the attempt, AfterResponse is called with the error so the attempt can be unwound.
+	BeforeSend(ctx context.Context, c *Call) error
+	// AfterResponse is called after each attempt with the response or the
+	// error which occurred performing the attempt.
+	AfterResponse(ctx context.Context, c *Call) error
+	// AfterDecode is called once the call has completed, after the response has
+	// been decoded or with the error which caused the call to fail.
+	AfterDecode(ctx context.Context, c *Call) error
+}
+
+// Func a function which is called at a stage of a call.
+type Func func(ctx context.Context, c *Call) error
+
+// call calls the function if it is defined.
+func (f Func) call(ctx context.Context, c *Call) error {
+	if f == nil {
+		return nil
+	}
+	return f(ctx, c)
+}
+
+// Funcs an Interceptor implementation which is defined from a set of functions
+// allowing an interceptor to only handle the stages it is interested in.
+// any of the functions can be nil.
+type Funcs struct {
+	OnBeforeEncode  Func
+	OnBeforeSend    Func
+	OnAfterResponse Func
+	OnAfterDecode   Func
+}
+
+// BeforeEncode implements Interceptor interface.
+func (f *Funcs) BeforeEncode(ctx context.Context, c *Call) error {
+	return f.OnBeforeEncode.call(ctx, c)
+}
+
+// BeforeSend implements Interceptor interface.
+func (f *Funcs) BeforeSend(ctx context.Context, c *Call) error { return f.OnBeforeSend.call(ctx, c) }
+
+// AfterResponse implements Interceptor interface.
+func (f *Funcs) AfterResponse(ctx context.Context, c *Call) error {
+	return f.OnAfterResponse.call(ctx, c)
+}
+
+// AfterDecode implements Interceptor interface.
+func (f *Funcs) AfterDecode(ctx context.Context, c *Call) error { return f.OnAfterDecode.call(ctx, c) }
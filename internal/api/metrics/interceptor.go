@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	_errors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
+)
+
+// fromCacheHeader the header set by httpcache on responses served from the cache.
+const fromCacheHeader = "X-From-Cache"
+
+const (
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+
+	// noStatus the status label used when an attempt received no response.
+	noStatus = "none"
+	// noEndpoint the endpoint label used when a call failed before an attempt was sent,
+	// i.e the request could not be encoded.
+	noEndpoint = "none"
+)
+
+// endpoint retrieves the endpoint label of the call.
+func endpoint(c *interceptor.Call) string {
+	if c.Endpoint == "" {
+		return noEndpoint
+	}
+	return c.Endpoint
+}
+
+// clientMetrics the metrics recorded on each call made through an API client.
+type clientMetrics struct {
+	requests Counter   // requests the amount of request attempts.
+	latency  Histogram // latency the duration of each request attempt.
+	retries  Counter   // retries the amount of attempts made after the first attempt.
+	cache    Counter   // cache the amount of cache hits and misses.
+	errors   Counter   // errors the amount of failed calls by error code.
+}
+
+// AfterResponse records the metrics for each attempt.
+func (m *clientMetrics) AfterResponse(_ context.Context, c *interceptor.Call) error {
+	status := noStatus
+	if c.Response != nil {
+		status = strconv.Itoa(c.Response.StatusCode)
+	}
+
+	e := endpoint(c)
+	m.requests.Inc(e, c.Method, status)
+	m.latency.Observe(c.Duration.Seconds(), e, c.Method)
+
+	if c.Attempt > 1 {
+		m.retries.Inc(e, c.Method)
+	}
+
+	// only GET requests are cacheable.
+	if c.Response != nil && c.Method == http.MethodGet {
+		result := cacheMiss
+		if c.Response.Header.Get(fromCacheHeader) != "" {
+			result = cacheHit
+		}
+		m.cache.Inc(e, result)
+	}
+
+	return nil
+}
+
+// AfterDecode records the error code for any failed call.
+func (m *clientMetrics) AfterDecode(_ context.Context, c *interceptor.Call) error {
+	if c.Err == nil {
+		return nil
+	}
+
+	code := errors.CodeUnknownError
+	var e *errors.Error
+	if _errors.As(c.Err, &e) {
+		code = e.Code
+	}
+
+	m.errors.Inc(endpoint(c), c.Method, string(code))
+	return nil
+}
+
+// Interceptor generates an interceptor which records metrics on each call made through an
+// API client into the supplied registry. The following metrics are recorded, labelled by endpoint,
+// or none when a call failed before an attempt was sent:
+//
+// - api_requests_total the amount of request attempts by method and status code.
+// - api_request_duration_seconds a histogram of the duration of each request attempt.
+// - api_retries_total the amount of attempts made after the first attempt, including fail overs.
+// - api_cache_requests_total the amount of cache hits and misses.
+// - api_errors_total the amount of failed calls by error code.
+func Interceptor(r Registry) interceptor.Interceptor {
+	m := &clientMetrics{
+		requests: r.Counter("api_requests_total",
+			"The amount of request attempts made to an upstream API.", "endpoint", "method", "status"),
+		latency: r.Histogram("api_request_duration_seconds",
+			"The duration of each request attempt made to an upstream API.", nil, "endpoint", "method"),
+		retries: r.Counter("api_retries_total",
+			"The amount of request attempts made after the first attempt.", "endpoint", "method"),
+		cache: r.Counter("api_cache_requests_total",
+			"The amount of requests served from and missing the cache.", "endpoint", "result"),
+		errors: r.Counter("api_errors_total",
+			"The amount of failed calls made to an upstream API by error code.", "endpoint", "method", "code"),
+	}
+
+	return &interceptor.Funcs{
+		OnAfterResponse: m.AfterResponse,
+		OnAfterDecode:   m.AfterDecode,
+	}
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	_errors "errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
+)
+
+const testEndpoint = "http://localhost:5555"
+
+// response generates a response with the supplied status code and headers.
+func response(code int, h ...string) *http.Response {
+	res := &http.Response{StatusCode: code, Header: make(http.Header)}
+	for i := 0; i+1 < len(h); i += 2 {
+		res.Header.Set(h[i], h[i+1])
+	}
+	return res
+}
+
+func TestInterceptor(t *testing.T) {
+	p := NewPrometheus()
+	i := Interceptor(p)
+	ctx := context.Background()
+
+	// a call which failed over, then was served from the cache.
+	c := &interceptor.Call{Method: http.MethodGet, Endpoint: testEndpoint}
+	for _, res := range []*http.Response{nil, response(http.StatusOK, fromCacheHeader, "1")} {
+		c.Attempt++
+		c.Response, c.Duration = res, 10*time.Millisecond
+		require.NoError(t, i.AfterResponse(ctx, c))
+	}
+	require.NoError(t, i.AfterDecode(ctx, c))
+
+	// a call which failed with a not found error.
+	c = &interceptor.Call{Method: http.MethodGet, Endpoint: testEndpoint, Attempt: 1}
+	c.Response = response(http.StatusNotFound)
+	require.NoError(t, i.AfterResponse(ctx, c))
+	c.Err = &errors.Error{Code: errors.CodeNotFound}
+	require.NoError(t, i.AfterDecode(ctx, c))
+
+	// a call which failed with an unknown error.
+	c = &interceptor.Call{Method: http.MethodPost, Endpoint: testEndpoint, Err: _errors.New("unknown")}
+	require.NoError(t, i.AfterDecode(ctx, c))
+
+	// a call which failed before an attempt was sent has no endpoint.
+	c = &interceptor.Call{Method: http.MethodPost, Err: &errors.Error{Code: errors.CodeEncodingError}}
+	require.NoError(t, i.AfterDecode(ctx, c))
+
+	b := &bytes.Buffer{}
+	_, err := p.WriteTo(b)
+	require.NoError(t, err)
+
+	out := b.String()
+	for _, expected := range []string{
+		`api_requests_total{endpoint="http://localhost:5555",method="GET",status="none"} 1`,
+		`api_requests_total{endpoint="http://localhost:5555",method="GET",status="200"} 1`,
+		`api_requests_total{endpoint="http://localhost:5555",method="GET",status="404"} 1`,
+		`api_request_duration_seconds_count{endpoint="http://localhost:5555",method="GET"} 3`,
+		`api_retries_total{endpoint="http://localhost:5555",method="GET"} 1`,
+		`api_cache_requests_total{endpoint="http://localhost:5555",result="hit"} 1`,
+		`api_cache_requests_total{endpoint="http://localhost:5555",result="miss"} 1`,
+		`api_errors_total{endpoint="http://localhost:5555",method="GET",code="not_found"} 1`,
+		`api_errors_total{endpoint="http://localhost:5555",method="POST",code="unknown_error"} 1`,
+		`api_errors_total{endpoint="none",method="POST",code="encoding_error"} 1`,
+	} {
+		assert.Contains(t, out, expected)
+	}
+	assert.NotContains(t, out, `endpoint=""`)
+}
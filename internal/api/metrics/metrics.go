@@ -0,0 +1,44 @@
+// Package metrics allows us to record metrics on the calls made through an API client
+// and on the requests handled by the server.
+//
+// metrics are recorded through the Registry interface so the backend can be swapped
+// out, an in-memory implementation is provided which renders the metrics in the
+// Prometheus text exposition format.
+package metrics
+
+// DefaultBuckets the default histogram buckets, in seconds, which are suitable for
+// recording the latency of HTTP requests.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Counter a metric which only ever increases.
+type Counter interface {
+	// Inc increments the counter by one for the series defined by the label values.
+	Inc(values ...string)
+	// Add increases the counter by delta for the series defined by the label values.
+	Add(delta float64, values ...string)
+}
+
+// Histogram a metric which samples observations into buckets.
+type Histogram interface {
+	// Observe records the value for the series defined by the label values.
+	Observe(value float64, values ...string)
+}
+
+// Registry creates and stores metrics.
+//
+// retrieving a metric with the same name more than once returns the same metric so
+// multiple clients can record into the same metrics. label values are supplied when
+// recording in the order the label names are defined.
+type Registry interface {
+	// Counter retrieves a counter with the supplied name and label names.
+	Counter(name, help string, labels ...string) Counter
+	// Histogram retrieves a histogram with the supplied name, buckets and label names.
+	// DefaultBuckets is used when no buckets are supplied.
+	Histogram(name, help string, buckets []float64, labels ...string) Histogram
+}
+
+// defaultRegistry the registry shared by the API clients and the server.
+var defaultRegistry = NewPrometheus()
+
+// Default returns the default registry.
+func Default() *Prometheus { return defaultRegistry }
@@ -0,0 +1,264 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	kindCounter   = "counter"
+	kindHistogram = "histogram"
+
+	// contentType the content type of the Prometheus text exposition format.
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// labelEscaper escapes label values as defined in the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// helpEscaper escapes help text as defined in the text exposition format.
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// series represents the recorded values for a single set of label values.
+type series struct {
+	values  []string  // values the label values.
+	value   float64   // value the value of a counter.
+	buckets []uint64  // buckets the (non-cumulative) count in each histogram bucket.
+	sum     float64   // sum the sum of all histogram observations.
+	count   uint64    // count the amount of histogram observations.
+	bounds  []float64 // bounds the upper bounds of each histogram bucket.
+}
+
+// family represents a metric and all of its series.
+type family struct {
+	mu      sync.Mutex
+	name    string             // name the name of the metric.
+	help    string             // help the description of the metric.
+	kind    string             // kind the type of metric.
+	labels  []string           // labels the label names.
+	buckets []float64          // buckets the histogram bucket upper bounds.
+	series  map[string]*series // series each series keyed by its label values.
+}
+
+// get retrieves the series for the supplied label values, creating it if required.
+// the family must be locked before calling this function.
+func (f *family) get(values []string) *series {
+	v := make([]string, len(f.labels))
+	copy(v, values)
+
+	key := strings.Join(v, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{values: v, bounds: f.buckets}
+		if f.kind == kindHistogram {
+			s.buckets = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Inc implements Counter interface.
+func (f *family) Inc(values ...string) { f.Add(1, values...) }
+
+// Add implements Counter interface.
+// negative values are ignored as a counter can only increase.
+func (f *family) Add(delta float64, values ...string) {
+	if delta < 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.get(values).value += delta
+}
+
+// Observe implements Histogram interface.
+func (f *family) Observe(value float64, values ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.get(values)
+	s.sum += value
+	s.count++
+	for i, b := range f.buckets {
+		if value <= b {
+			s.buckets[i]++
+			break
+		}
+	}
+}
+
+// write writes the family in the text exposition format.
+func (f *family) write(w *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.series) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, helpEscaper.Replace(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := f.series[k]
+		if f.kind == kindCounter {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, f.labelSet(s.values, "", ""), formatFloat(s.value))
+			continue
+		}
+
+		var cumulative uint64
+		for i, b := range s.bounds {
+			cumulative += s.buckets[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelSet(s.values, "le", formatFloat(b)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelSet(s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, f.labelSet(s.values, "", ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, f.labelSet(s.values, "", ""), s.count)
+	}
+}
+
+// labelSet formats the label values as a label set, optionally appending an extra label.
+func (f *family) labelSet(values []string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, v := range values {
+		pairs = append(pairs, f.labels[i]+`="`+labelEscaper.Replace(v)+`"`)
+	}
+
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatFloat formats a float as defined in the text exposition format.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Prometheus an in-memory Registry implementation which renders
+// the recorded metrics in the Prometheus text exposition format.
+//
+// it also implements http.Handler so it can be served directly.
+type Prometheus struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// Counter implements Registry interface.
+func (p *Prometheus) Counter(name, help string, labels ...string) Counter {
+	return p.family(name, help, kindCounter, nil, labels)
+}
+
+// Histogram implements Registry interface.
+func (p *Prometheus) Histogram(name, help string, buckets []float64, labels ...string) Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+	return p.family(name, help, kindHistogram, b, labels)
+}
+
+// family retrieves or creates a metric family.
+// this panics if a metric is retrieved with a different type or labels than it was
+// first defined with, as that is always a programming error.
+func (p *Prometheus) family(name, help, kind string, buckets []float64, labels []string) *family {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if f, ok := p.families[name]; ok {
+		if f.kind != kind || strings.Join(f.labels, ",") != strings.Join(labels, ",") {
+			panic("metric " + name + " is already defined with a different type or labels")
+		}
+		return f
+	}
+
+	f := &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	p.families[name] = f
+	return f
+}
+
+// WriteTo writes every metric in the text exposition format to w
+// the metrics are sorted by name so the output is deterministic.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.families))
+	for n := range p.families {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, n := range names {
+		p.families[n].write(bw)
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP implements http.Handler interface.
+// writes the metrics in the text exposition format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	p.WriteTo(w)
+}
+
+// NewPrometheus initialises a new, empty, Prometheus registry.
+func NewPrometheus() *Prometheus {
+	return &Prometheus{families: make(map[string]*family)}
+}
+
+// countingWriter a writer which counts the amount of bytes written.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer interface.
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
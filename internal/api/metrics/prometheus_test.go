@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheus_WriteTo(t *testing.T) {
+	p := NewPrometheus()
+	c := p.Counter("requests_total", "The amount of requests.", "method", "path")
+	c.Inc("GET", "/status")
+	c.Add(2, "GET", "/status")
+	c.Add(-1, "GET", "/status") // ignored, counters can only increase.
+	c.Inc("POST", `/"quoted"`)
+
+	h := p.Histogram("duration_seconds", "The duration\nof requests.", []float64{1, 0.1}, "method")
+	h.Observe(0.05, "GET")
+	h.Observe(0.5, "GET")
+	h.Observe(5, "GET")
+
+	// metrics with no recorded series are not written.
+	p.Counter("unused_total", "An unused counter.")
+
+	b := &bytes.Buffer{}
+	n, err := p.WriteTo(b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(b.Len()), n)
+	assert.Equal(t, `# HELP duration_seconds The duration\nof requests.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{method="GET",le="0.1"} 1
+duration_seconds_bucket{method="GET",le="1"} 2
+duration_seconds_bucket{method="GET",le="+Inf"} 3
+duration_seconds_sum{method="GET"} 5.55
+duration_seconds_count{method="GET"} 3
+# HELP requests_total The amount of requests.
+# TYPE requests_total counter
+requests_total{method="GET",path="/status"} 3
+requests_total{method="POST",path="/\"quoted\""} 1
+`, b.String())
+}
+
+func TestPrometheus_Counter(t *testing.T) {
+	p := NewPrometheus()
+	c := p.Counter("requests_total", "The amount of requests.", "method")
+
+	// retrieving the same metric returns the same counter.
+	assert.Equal(t, c, p.Counter("requests_total", "The amount of requests.", "method"))
+
+	assert.Panics(t, func() { p.Counter("requests_total", "The amount of requests.", "path") })
+	assert.Panics(t, func() { p.Histogram("requests_total", "The amount of requests.", nil, "method") })
+}
+
+func TestPrometheus_ServeHTTP(t *testing.T) {
+	p := NewPrometheus()
+	p.Counter("requests_total", "The amount of requests.").Inc()
+
+	r := httptest.NewRecorder()
+	p.ServeHTTP(r, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, r.Code)
+	assert.Equal(t, contentType, r.Header().Get("Content-Type"))
+	assert.Contains(t, r.Body.String(), "requests_total 1\n")
+}
@@ -8,8 +8,13 @@ import (
 
 	"github.com/jacklaaa89/pokeapi/internal/api/auth"
 	"github.com/jacklaaa89/pokeapi/internal/api/backoff"
+	"github.com/jacklaaa89/pokeapi/internal/api/endpoint"
 	"github.com/jacklaaa89/pokeapi/internal/api/format"
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
 	"github.com/jacklaaa89/pokeapi/internal/api/log"
+	"github.com/jacklaaa89/pokeapi/internal/api/metrics"
+	"github.com/jacklaaa89/pokeapi/internal/api/redact"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
 )
 
 // WithUserAgent updates the API options with the supplied user-agent.
@@ -87,3 +92,51 @@ func WithTimeout(t time.Duration) APIOption {
 		o.Timeout = t
 	})
 }
+
+// WithEndpoints overrides the endpoint supplied when initialising the client
+// with a pool of endpoints to fail over between.
+func WithEndpoints(p endpoint.Pool) APIOption {
+	return newAPIOption(func(o *Options) {
+		if p == nil {
+			return
+		}
+		o.Endpoints = p
+	})
+}
+
+// WithInterceptors appends to the interceptors which are called around each stage of a call.
+func WithInterceptors(i ...interceptor.Interceptor) APIOption {
+	return newAPIOption(func(o *Options) {
+		o.Interceptors = append(o.Interceptors, i...)
+	})
+}
+
+// WithMetrics records metrics on each call into the supplied registry.
+func WithMetrics(r metrics.Registry) APIOption {
+	return newAPIOption(func(o *Options) {
+		if r == nil {
+			return
+		}
+		o.Interceptors = append(o.Interceptors, metrics.Interceptor(r))
+	})
+}
+
+// WithTracer traces each call using the supplied tracer, propagating the trace to the API.
+func WithTracer(t *trace.Tracer) APIOption {
+	return newAPIOption(func(o *Options) {
+		if t == nil {
+			return
+		}
+		o.Interceptors = append(o.Interceptors, trace.Interceptor(t))
+	})
+}
+
+// WithRedactor overrides the redactor used to scrub secrets from errors and log output.
+func WithRedactor(r redact.Redactor) APIOption {
+	return newAPIOption(func(o *Options) {
+		if r == nil {
+			return
+		}
+		o.Redactor = r
+	})
+}
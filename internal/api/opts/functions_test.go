@@ -14,11 +14,16 @@ import (
 
 	"github.com/jacklaaa89/pokeapi/internal/api/auth"
 	"github.com/jacklaaa89/pokeapi/internal/api/backoff"
+	"github.com/jacklaaa89/pokeapi/internal/api/endpoint"
 	"github.com/jacklaaa89/pokeapi/internal/api/format"
 	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
 	"github.com/jacklaaa89/pokeapi/internal/api/log"
 	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
 	"github.com/jacklaaa89/pokeapi/internal/api/log/zap"
+	"github.com/jacklaaa89/pokeapi/internal/api/metrics"
+	"github.com/jacklaaa89/pokeapi/internal/api/redact"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
 )
 
 var config = _zap.NewProductionConfig()
@@ -217,3 +222,58 @@ func TestWithTimeout(t *testing.T) {
 	opts := Apply(WithTimeout(15 * time.Second))
 	assert.Equal(t, 15*time.Second, opts.Timeout)
 }
+
+func TestWithEndpoints(t *testing.T) {
+	p, err := endpoint.Failover("http://primary.local", "http://secondary.local")
+	require.NoError(t, err)
+
+	tt := []struct {
+		Name     string
+		Pool     endpoint.Pool
+		Expected func(t *testing.T, o *Options)
+	}{
+		{
+			Name: "NewPool",
+			Pool: p,
+			Expected: func(t *testing.T, o *Options) {
+				assert.Equal(t, p, o.Endpoints)
+			},
+		},
+		{
+			Name: "Nil",
+			Pool: nil,
+			Expected: func(t *testing.T, o *Options) {
+				assert.Nil(t, o.Endpoints)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			o := Apply(WithEndpoints(tc.Pool))
+			tc.Expected(st, o)
+		})
+	}
+}
+
+func TestWithInterceptors(t *testing.T) {
+	a, b := &interceptor.Funcs{}, &interceptor.Funcs{}
+	opts := Apply(WithInterceptors(a), WithInterceptors(b))
+	assert.Equal(t, []interceptor.Interceptor{a, b}, opts.Interceptors)
+}
+
+func TestWithMetrics(t *testing.T) {
+	assert.Len(t, Apply(WithMetrics(metrics.NewPrometheus())).Interceptors, 1)
+	assert.Empty(t, Apply(WithMetrics(nil)).Interceptors)
+}
+
+func TestWithTracer(t *testing.T) {
+	assert.Len(t, Apply(WithTracer(trace.New(nil))).Interceptors, 1)
+	assert.Empty(t, Apply(WithTracer(nil)).Interceptors)
+}
+
+func TestWithRedactor(t *testing.T) {
+	r := redact.New(nil, []string{"sig"}, nil)
+	assert.Equal(t, r, Apply(WithRedactor(r)).Redactor)
+	assert.Equal(t, redact.Default(), Apply(WithRedactor(nil)).Redactor)
+}
@@ -12,10 +12,13 @@ import (
 
 	"github.com/jacklaaa89/pokeapi/internal/api/auth"
 	"github.com/jacklaaa89/pokeapi/internal/api/backoff"
+	"github.com/jacklaaa89/pokeapi/internal/api/endpoint"
 	"github.com/jacklaaa89/pokeapi/internal/api/format"
 	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
 	"github.com/jacklaaa89/pokeapi/internal/api/log"
 	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/api/redact"
 )
 
 // zeroTimeout defines a zero timeout.
@@ -32,9 +35,20 @@ type Options struct {
 	Logger            log.Logger       // Logger the logger to use when performing requests.
 	Language          language.Tag     // Language language used to set the Accept-Language header.
 
+	// Endpoints defines the pool of endpoints to send requests to, when defined this
+	// overrides the endpoint supplied when initialising the client.
+	Endpoints endpoint.Pool
+
 	// Timeout defines the timeout which is applied to each request, a timeout of zero
 	// represents no timeout is applied.
 	Timeout time.Duration
+
+	// Interceptors the interceptors which are called around each stage of a call
+	// in the order they are defined.
+	Interceptors []interceptor.Interceptor
+
+	// Redactor scrubs secrets from the errors returned from each call and the log output.
+	Redactor redact.Redactor
 }
 
 // APIOption configures how we set up the API.
@@ -72,6 +86,9 @@ func Apply(opts ...APIOption) *Options {
 		Logger:            fmt.New(fmt.LevelNone),
 		Language:          language.BritishEnglish,
 		Timeout:           zeroTimeout,
+		Endpoints:         nil,
+		Interceptors:      nil,
+		Redactor:          redact.Default(),
 	}
 
 	for _, opt := range opts {
@@ -0,0 +1,43 @@
+package redact
+
+import (
+	"fmt"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/log"
+)
+
+// logger a log.Logger implementation which masks secrets before
+// the message is written to the wrapped logger.
+type logger struct {
+	l log.Logger // l the wrapped logger.
+	r Redactor   // r the redactor used to mask each message.
+}
+
+// Debugf implements log.Logger interface.
+func (l *logger) Debugf(format string, v ...interface{}) { l.l.Debugf("%s", l.mask(format, v)) }
+
+// Errorf implements log.Logger interface.
+func (l *logger) Errorf(format string, v ...interface{}) { l.l.Errorf("%s", l.mask(format, v)) }
+
+// Infof implements log.Logger interface.
+func (l *logger) Infof(format string, v ...interface{}) { l.l.Infof("%s", l.mask(format, v)) }
+
+// Warnf implements log.Logger interface.
+func (l *logger) Warnf(format string, v ...interface{}) { l.l.Warnf("%s", l.mask(format, v)) }
+
+// mask formats the message and masks any secrets.
+func (l *logger) mask(format string, v []interface{}) string {
+	return l.r.String(fmt.Sprintf(format, v...))
+}
+
+// Logger wraps the logger so that secrets are masked from each message using the redactor.
+func Logger(l log.Logger, r Redactor) log.Logger {
+	if l == nil || r == nil {
+		return l
+	}
+
+	if w, ok := l.(*logger); ok {
+		l = w.l
+	}
+	return &logger{l: l, r: r}
+}
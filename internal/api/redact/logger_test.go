@@ -0,0 +1,25 @@
+package redact
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+)
+
+func TestLogger(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := Logger(fmt.NewWithOutputs(fmt.LevelDebug, b, b), Default())
+
+	l.Errorf("request %s failed", "/translate?api_key=secret")
+	l.Debugf("%d%%", 100)
+	assert.NotContains(t, b.String(), "secret")
+	assert.Contains(t, b.String(), "/translate?api_key=%5BREDACTED%5D")
+	assert.Contains(t, b.String(), "100%")
+
+	// wrapping a redacting logger does not redact twice.
+	assert.Equal(t, l.(*logger).l, Logger(l, Default()).(*logger).l)
+	assert.Nil(t, Logger(nil, Default()))
+}
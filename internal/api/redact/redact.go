@@ -0,0 +1,243 @@
+// Package redact scrubs secrets, such as credentials, from the data we record about the
+// requests we make, i.e errors, log output and traces, before it leaves the process.
+//
+// header names, query parameters and JSON body fields are matched case-insensitively
+// and their values replaced with Mask.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+)
+
+// Mask the value secrets are replaced with.
+const Mask = "[REDACTED]"
+
+var (
+	// DefaultHeaders the headers which are redacted by default.
+	DefaultHeaders = []string{
+		"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+		"X-Api-Key", "X-Funtranslations-Api-Secret", "X-Signature",
+	}
+	// DefaultQuery the query parameters which are redacted by default.
+	DefaultQuery = []string{
+		"api_key", "apikey", "key", "token", "access_token", "client_secret", "secret", "password", "signature",
+	}
+	// DefaultFields the JSON body fields which are redacted by default.
+	DefaultFields = []string{
+		"password", "secret", "token", "access_token", "refresh_token", "client_secret", "api_key", "apikey",
+	}
+)
+
+// Redactor scrubs secrets from the data recorded about a request.
+type Redactor interface {
+	// Header returns a copy of the headers with any secret values masked.
+	Header(h http.Header) http.Header
+	// URL returns the URL, or path and query string, with any secret query parameters masked.
+	URL(u string) string
+	// Body returns a copy of a JSON or form encoded body with any secret fields masked.
+	// bodies in any other format are returned as is.
+	Body(b []byte) []byte
+	// String masks any secrets found in free text, i.e a log line.
+	String(s string) string
+	// Error masks any secrets recorded on the error, the error is modified in place.
+	Error(e *errors.Error)
+}
+
+// redactor the internal implementation of a Redactor.
+type redactor struct {
+	headers map[string]struct{} // headers the canonical header names to redact.
+	query   map[string]struct{} // query the lowercase query parameters to redact.
+	fields  map[string]struct{} // fields the lowercase JSON fields to redact.
+
+	text []pattern // text the patterns which match secrets in free text.
+}
+
+// pattern matches a secret in free text.
+type pattern struct {
+	re          *regexp.Regexp // re the expression, the first group is the text to keep.
+	replacement string         // replacement the replacement for the match.
+}
+
+// Header implements Redactor interface.
+func (r *redactor) Header(h http.Header) http.Header {
+	out := h.Clone()
+	for k, v := range out {
+		if _, ok := r.headers[http.CanonicalHeaderKey(k)]; ok {
+			for i := range v {
+				v[i] = Mask
+			}
+		}
+	}
+	return out
+}
+
+// URL implements Redactor interface.
+func (r *redactor) URL(u string) string {
+	i := strings.IndexByte(u, '?')
+	if i < 0 {
+		return u
+	}
+
+	fragment := ""
+	if j := strings.IndexByte(u[i:], '#'); j >= 0 {
+		fragment, u = u[i+j:], u[:i+j]
+	}
+
+	return u[:i+1] + r.rawQuery(u[i+1:]) + fragment
+}
+
+// rawQuery masks the secret parameters in a raw query string, the order of the
+// parameters and the encoding of the other parameters is retained.
+func (r *redactor) rawQuery(q string) string {
+	pairs := strings.Split(q, "&")
+	for i, p := range pairs {
+		k := p
+		if j := strings.IndexByte(p, '='); j >= 0 {
+			k = p[:j]
+		}
+
+		name := k
+		if n, err := url.QueryUnescape(k); err == nil {
+			name = n
+		}
+
+		if _, ok := r.query[strings.ToLower(name)]; ok {
+			pairs[i] = k + "=" + url.QueryEscape(Mask)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// Body implements Redactor interface.
+func (r *redactor) Body(b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err == nil {
+		if !r.redactJSON(v) {
+			return b
+		}
+
+		buf := &bytes.Buffer{}
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		if enc.Encode(v) == nil {
+			return bytes.TrimRight(buf.Bytes(), "\n")
+		}
+	}
+
+	// form encoded bodies are redacted in the same way as a query string.
+	if bytes.IndexByte(b, '=') >= 0 && !bytes.ContainsAny(b, " \t\r\n{") {
+		if _, err := url.ParseQuery(string(b)); err == nil {
+			return []byte(r.rawQuery(string(b)))
+		}
+	}
+
+	// bodies which are not valid JSON, i.e a truncated sample, fall back to matching text.
+	return []byte(r.String(string(b)))
+}
+
+// redactJSON masks the secret fields in a decoded JSON value, reporting whether any were masked.
+func (r *redactor) redactJSON(v interface{}) (masked bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, value := range t {
+			if _, ok := r.fields[strings.ToLower(k)]; ok {
+				t[k], masked = Mask, true
+				continue
+			}
+			masked = r.redactJSON(value) || masked
+		}
+	case []interface{}:
+		for _, value := range t {
+			masked = r.redactJSON(value) || masked
+		}
+	}
+	return
+}
+
+// String implements Redactor interface.
+func (r *redactor) String(s string) string {
+	for _, p := range r.text {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// Error implements Redactor interface.
+func (r *redactor) Error(e *errors.Error) {
+	if e == nil {
+		return
+	}
+
+	e.Resource = r.URL(e.Resource)
+	e.Request = r.Body(e.Request)
+	e.Response = r.Body(e.Response)
+	e.Source = r.String(e.Source)
+}
+
+// New initialises a new redactor which masks the supplied header names, query parameters
+// and JSON body fields. the defaults are not included unless supplied.
+func New(headers, query, fields []string) Redactor {
+	r := &redactor{
+		headers: make(map[string]struct{}, len(headers)),
+		query:   make(map[string]struct{}, len(query)),
+		fields:  make(map[string]struct{}, len(fields)),
+	}
+
+	names := make([]string, 0, len(headers))
+	for _, h := range headers {
+		r.headers[http.CanonicalHeaderKey(h)] = struct{}{}
+		names = append(names, regexp.QuoteMeta(h))
+	}
+
+	params := make([]string, 0, len(query))
+	for _, q := range query {
+		r.query[strings.ToLower(q)] = struct{}{}
+		params = append(params, regexp.QuoteMeta(q))
+	}
+
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		r.fields[strings.ToLower(f)] = struct{}{}
+		keys = append(keys, regexp.QuoteMeta(f))
+	}
+
+	if len(names) > 0 {
+		// i.e Authorization: Bearer token
+		r.text = append(r.text, pattern{
+			re:          regexp.MustCompile(`(?i)(\b(?:` + strings.Join(names, "|") + `)\s*[:=]\s*)[^\r\n,;"]+`),
+			replacement: "${1}" + Mask,
+		})
+	}
+	if len(params) > 0 {
+		// i.e ?api_key=secret&
+		r.text = append(r.text, pattern{
+			re:          regexp.MustCompile(`(?i)([?&](?:` + strings.Join(params, "|") + `)=)[^&#\s"]*`),
+			replacement: "${1}" + url.QueryEscape(Mask),
+		})
+	}
+	if len(keys) > 0 {
+		// i.e "password":"secret"
+		r.text = append(r.text, pattern{
+			re:          regexp.MustCompile(`(?i)("(?:` + strings.Join(keys, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"?`),
+			replacement: `${1}"` + Mask + `"`,
+		})
+	}
+	return r
+}
+
+// defaultRedactor the redactor using the default keys.
+var defaultRedactor = New(DefaultHeaders, DefaultQuery, DefaultFields)
+
+// Default retrieves the redactor which masks the default header names, query parameters and JSON body fields.
+func Default() Redactor { return defaultRedactor }
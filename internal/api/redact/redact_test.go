@@ -0,0 +1,116 @@
+package redact
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+)
+
+func TestRedactor_Header(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer token")
+	h.Set("x-funtranslations-api-secret", "secret")
+	h.Set("Accept", "application/json")
+
+	out := Default().Header(h)
+	assert.Equal(t, Mask, out.Get("Authorization"))
+	assert.Equal(t, Mask, out.Get("X-Funtranslations-Api-Secret"))
+	assert.Equal(t, "application/json", out.Get("Accept"))
+
+	// the original headers are not modified.
+	assert.Equal(t, "Bearer token", h.Get("Authorization"))
+}
+
+func TestRedactor_URL(t *testing.T) {
+	tt := []struct {
+		Name     string
+		URL      string
+		Expected string
+	}{
+		{Name: "NoQuery", URL: "/pokemon/ditto", Expected: "/pokemon/ditto"},
+		{Name: "NoSecret", URL: "/translate?text=hello+world", Expected: "/translate?text=hello+world"},
+		{
+			Name:     "Secret",
+			URL:      "https://api.com/translate?text=hello&API_KEY=secret&token",
+			Expected: "https://api.com/translate?text=hello&API_KEY=%5BREDACTED%5D&token=%5BREDACTED%5D",
+		},
+		{
+			Name:     "Fragment",
+			URL:      "/path?key=secret#section",
+			Expected: "/path?key=%5BREDACTED%5D#section",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, Default().URL(tc.URL))
+		})
+	}
+}
+
+func TestRedactor_Body(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Body     string
+		Expected string
+	}{
+		{Name: "Empty", Body: "", Expected: ""},
+		{Name: "NoSecret", Body: `{"text":"hello"}`, Expected: `{"text":"hello"}`},
+		{
+			Name:     "Nested",
+			Body:     `{"user":{"Password":"hunter2","name":"ash"},"tokens":[{"access_token":"abc"}]}`,
+			Expected: `{"tokens":[{"access_token":"[REDACTED]"}],"user":{"Password":"[REDACTED]","name":"ash"}}`,
+		},
+		{
+			Name:     "Truncated",
+			Body:     `{"name":"ash","client_secret":"abc","text":"long...`,
+			Expected: `{"name":"ash","client_secret":"[REDACTED]","text":"long...`,
+		},
+		{
+			Name:     "Form",
+			Body:     `client_secret=abc&grant_type=client_credentials`,
+			Expected: `client_secret=%5BREDACTED%5D&grant_type=client_credentials`,
+		},
+		{Name: "Text", Body: `not found`, Expected: `not found`},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, string(Default().Body([]byte(tc.Body))))
+		})
+	}
+}
+
+func TestRedactor_String(t *testing.T) {
+	r := Default()
+	assert.Equal(t,
+		`Get "http://localhost/translate?text=hi&api_key=%5BREDACTED%5D": connection refused`,
+		r.String(`Get "http://localhost/translate?text=hi&api_key=secret": connection refused`),
+	)
+	assert.Equal(t, "Authorization: [REDACTED]", r.String("Authorization: Bearer token"))
+	assert.Equal(t, `{"password":"[REDACTED]"}`, r.String(`{"password":"hunter2"}`))
+
+	// only the configured keys are redacted.
+	r = New(nil, []string{"sig"}, nil)
+	assert.Equal(t, "/path?sig=%5BREDACTED%5D&api_key=secret", r.String("/path?sig=abc&api_key=secret"))
+}
+
+func TestRedactor_Error(t *testing.T) {
+	e := &errors.Error{
+		Resource: "/translate?api_key=secret",
+		Request:  []byte(`{"password":"hunter2"}`),
+		Response: []byte(`{"token":"abc"}`),
+		Source:   `Get "/translate?api_key=secret": EOF`,
+	}
+
+	Default().Error(e)
+	assert.Equal(t, "/translate?api_key=%5BREDACTED%5D", e.Resource)
+	assert.Equal(t, `{"password":"[REDACTED]"}`, string(e.Request))
+	assert.Equal(t, `{"token":"[REDACTED]"}`, string(e.Response))
+	assert.Equal(t, `Get "/translate?api_key=%5BREDACTED%5D": EOF`, e.Source)
+
+	assert.NotPanics(t, func() { Default().Error(nil) })
+}
@@ -0,0 +1,95 @@
+// Package requestid allows a request id to be carried in a context so that the
+// requests made to an upstream API can be correlated with the request which caused them.
+//
+// the ids for upstream requests are derived from the id assigned to the context by
+// suffixing a sequence number, i.e a request with the id abc which makes two upstream
+// requests sends the ids abc.1 and abc.2, this makes the logs correlatable across hops.
+package requestid
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// maxLength the maximum length of an id received from another service.
+const maxLength = 128
+
+// separator the separator between the parent id and the sequence number.
+const separator = "."
+
+// contextKey the context key to use for the request id.
+type contextKey struct{}
+
+// value the value assigned to the context.
+type value struct {
+	id  string  // id the request id.
+	seq *uint64 // seq the amount of ids derived from the request id.
+}
+
+// New generates a new random request id.
+func New() string { return uuid.New().String() }
+
+// Valid reports whether the id is safe to use as a request id, this is used to validate
+// ids received from another service before they are trusted. a valid id is between 1
+// and 128 characters made up of letters, digits and any of the characters - _ . :
+func Valid(id string) bool {
+	if len(id) == 0 || len(id) > maxLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext generates a context with the request id assigned.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, &value{id: id, seq: new(uint64)})
+}
+
+// FromContext retrieves the request id assigned to the context.
+// false is returned if there is no request id assigned.
+func FromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	v, ok := ctx.Value(contextKey{}).(*value)
+	if !ok {
+		return "", false
+	}
+	return v.id, true
+}
+
+// Derive derives a new id for an upstream request from the request id assigned to the context
+// each derived id is unique within the context. false is returned if there is no request id
+// assigned to the context.
+//
+// the request id is truncated so that the derived id is never longer than maxLength, otherwise
+// an upstream service which validates the id in the same way would replace it.
+func Derive(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	v, ok := ctx.Value(contextKey{}).(*value)
+	if !ok {
+		return "", false
+	}
+
+	suffix := separator + strconv.FormatUint(atomic.AddUint64(v.seq, 1), 10)
+	id := v.id
+	if len(id)+len(suffix) > maxLength {
+		id = id[:maxLength-len(suffix)]
+	}
+	return id + suffix, true
+}
@@ -0,0 +1,63 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	tt := []struct {
+		Name     string
+		ID       string
+		Expected bool
+	}{
+		{Name: "UUID", ID: uuid.New().String(), Expected: true},
+		{Name: "Derived", ID: "abc-123.1", Expected: true},
+		{Name: "Characters", ID: "1-5759e988:bd862e3fe_1.A", Expected: true},
+		{Name: "Empty", ID: "", Expected: false},
+		{Name: "TooLong", ID: strings.Repeat("a", maxLength+1), Expected: false},
+		{Name: "Whitespace", ID: "abc 123", Expected: false},
+		{Name: "HeaderInjection", ID: "abc\r\nX-Admin: true", Expected: false},
+		{Name: "Unicode", ID: "abcé", Expected: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, Valid(tc.ID))
+		})
+	}
+}
+
+func TestDerive(t *testing.T) {
+	_, ok := Derive(context.Background())
+	assert.False(t, ok)
+
+	_, ok = FromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := NewContext(context.Background(), "abc")
+	id, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "abc", id)
+
+	for _, expected := range []string{"abc.1", "abc.2", "abc.3"} {
+		id, ok := Derive(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, expected, id)
+	}
+
+	// a new context starts a new sequence.
+	id, _ = Derive(NewContext(ctx, "def"))
+	assert.Equal(t, "def.1", id)
+
+	// the parent id is truncated so that the derived id remains valid.
+	long := strings.Repeat("a", maxLength)
+	id, _ = Derive(NewContext(ctx, long))
+	assert.True(t, Valid(id))
+	assert.Len(t, id, maxLength)
+	assert.Equal(t, long[:maxLength-2]+".1", id)
+}
@@ -0,0 +1,71 @@
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// Exporter receives each span once it has finished.
+type Exporter interface {
+	// Export exports the finished span, the span must not be modified.
+	Export(s *Span)
+}
+
+// discard an Exporter implementation which discards every span.
+type discard struct{}
+
+// Export implements Exporter interface.
+func (discard) Export(*Span) {}
+
+// Discard returns an exporter which discards every span.
+func Discard() Exporter { return discard{} }
+
+// jsonExporter an Exporter implementation which writes each span as a line of JSON.
+type jsonExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Export implements Exporter interface.
+func (j *jsonExporter) Export(s *Span) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	json.NewEncoder(j.w).Encode(s)
+}
+
+// JSON returns an exporter which writes each span as a line of JSON to w, i.e os.Stdout.
+func JSON(w io.Writer) Exporter { return &jsonExporter{w: w} }
+
+// Memory an Exporter implementation which stores each span in memory
+// this is useful to make assertions on spans in tests.
+type Memory struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// Export implements Exporter interface.
+func (m *Memory) Export(s *Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans = append(m.spans, s)
+}
+
+// Spans returns every exported span in the order they finished.
+func (m *Memory) Spans() []*Span {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*Span, len(m.spans))
+	copy(out, m.spans)
+	return out
+}
+
+// Reset removes every exported span.
+func (m *Memory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans = nil
+}
+
+// NewMemory initialises a new in-memory exporter.
+func NewMemory() *Memory { return &Memory{} }
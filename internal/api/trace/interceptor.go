@@ -0,0 +1,89 @@
+package trace
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
+)
+
+// callSpanKey the key the span for the whole call is stored against.
+type callSpanKey struct{}
+
+// attemptSpanKey the key the span for the current attempt is stored against.
+type attemptSpanKey struct{}
+
+// clientTracer traces each call made through an API client.
+type clientTracer struct{ t *Tracer }
+
+// BeforeEncode starts the span for the whole call, as a child of any span assigned to ctx.
+func (ct *clientTracer) BeforeEncode(ctx context.Context, c *interceptor.Call) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_, s := ct.t.Start(ctx, c.Method+" "+c.Path)
+	s.SetAttribute("http.method", c.Method)
+	s.SetAttribute("http.path", c.Path)
+	s.SetAttribute("request_id", c.RequestID)
+	c.Set(callSpanKey{}, s)
+	return nil
+}
+
+// BeforeSend starts the span for the attempt and propagates it to the upstream API
+// using the traceparent header.
+func (ct *clientTracer) BeforeSend(ctx context.Context, c *interceptor.Call) error {
+	parent, ok := c.Get(callSpanKey{}).(*Span)
+	if !ok {
+		return nil
+	}
+
+	_, s := ct.t.Start(ContextWithSpan(context.Background(), parent), "attempt "+strconv.Itoa(c.Attempt))
+	s.SetAttribute("endpoint", c.Endpoint)
+	s.SetAttribute("attempt", strconv.Itoa(c.Attempt))
+	s.SetAttribute("retry", strconv.Itoa(c.Retry))
+	c.Set(attemptSpanKey{}, s)
+
+	if c.Request != nil {
+		Inject(s.Context, c.Request.Header)
+	}
+	return nil
+}
+
+// AfterResponse ends the span for the attempt.
+func (ct *clientTracer) AfterResponse(_ context.Context, c *interceptor.Call) error {
+	s, ok := c.Get(attemptSpanKey{}).(*Span)
+	if !ok {
+		return nil
+	}
+
+	if c.Response != nil {
+		s.SetAttribute("http.status_code", strconv.Itoa(c.Response.StatusCode))
+	}
+	s.SetError(c.Err)
+	s.Finish()
+	return nil
+}
+
+// AfterDecode ends the span for the whole call.
+func (ct *clientTracer) AfterDecode(_ context.Context, c *interceptor.Call) error {
+	s, ok := c.Get(callSpanKey{}).(*Span)
+	if !ok {
+		return nil
+	}
+
+	s.SetAttribute("attempts", strconv.Itoa(c.Attempt))
+	s.SetError(c.Err)
+	s.Finish()
+	return nil
+}
+
+// Interceptor generates an interceptor which traces each call made through an API client.
+//
+// a span is started for the whole call, as a child of any span assigned to the context
+// supplied to the call, and a child span is started for each attempt, including retries
+// and fail overs. Each attempt propagates its span to the upstream API using the
+// traceparent and tracestate headers.
+func Interceptor(t *Tracer) interceptor.Interceptor {
+	return &clientTracer{t: t}
+}
@@ -0,0 +1,86 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
+)
+
+func TestInterceptor(t *testing.T) {
+	m := NewMemory()
+	tr := New(m)
+	i := Interceptor(tr)
+
+	ctx, parent := tr.Start(context.Background(), "handler")
+	req, err := http.NewRequest(http.MethodGet, "/pokemon-species/ditto", nil)
+	require.NoError(t, err)
+
+	c := &interceptor.Call{Method: http.MethodGet, Path: "/pokemon-species/ditto"}
+	require.NoError(t, i.BeforeEncode(ctx, c))
+	c.Request = req
+
+	// a failed attempt, followed by a successful retry.
+	var traceparents []string
+	for _, code := range []int{http.StatusBadGateway, http.StatusOK} {
+		c.Attempt++
+		require.NoError(t, i.BeforeSend(ctx, c))
+		traceparents = append(traceparents, req.Header.Get(TraceparentHeader))
+
+		c.Response, c.Err = &http.Response{StatusCode: code}, nil
+		if code != http.StatusOK {
+			c.Err = errors.New("bad gateway")
+		}
+		require.NoError(t, i.AfterResponse(ctx, c))
+	}
+
+	c.Err = nil
+	require.NoError(t, i.AfterDecode(ctx, c))
+
+	spans := m.Spans()
+	require.Len(t, spans, 3)
+
+	call := spans[2]
+	assert.Equal(t, "GET /pokemon-species/ditto", call.Name)
+	assert.Equal(t, parent.SpanID, call.ParentID)
+	assert.Equal(t, "2", call.Attributes["attempts"])
+
+	for n, s := range spans[:2] {
+		assert.Equal(t, parent.TraceID, s.TraceID)
+		assert.Equal(t, call.SpanID, s.ParentID)
+		// each attempt propagates its own span.
+		assert.Equal(t, s.Context.Traceparent(), traceparents[n])
+	}
+
+	assert.Equal(t, "bad gateway", spans[0].Error)
+	assert.Equal(t, "502", spans[0].Attributes["http.status_code"])
+	assert.Empty(t, spans[1].Error)
+}
+
+// TestInterceptor_Unwound ensures the span for an attempt is finished when an interceptor
+// after the tracer fails the attempt before it is sent, i.e when the quota is exhausted.
+func TestInterceptor_Unwound(t *testing.T) {
+	m := NewMemory()
+	failure := errors.New("quota exhausted")
+	i := interceptor.Chain(Interceptor(New(m)), &interceptor.Funcs{
+		OnBeforeSend: func(context.Context, *interceptor.Call) error { return failure },
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "/translate/yoda.json", nil)
+	require.NoError(t, err)
+
+	ctx, c := context.Background(), &interceptor.Call{Method: http.MethodGet, Path: "/translate/yoda.json"}
+	require.NoError(t, i.BeforeEncode(ctx, c))
+	c.Request, c.Attempt = req, 1
+	assert.Equal(t, failure, i.BeforeSend(ctx, c))
+
+	spans := m.Spans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "attempt 1", spans[0].Name)
+	assert.Equal(t, "quota exhausted", spans[0].Error)
+}
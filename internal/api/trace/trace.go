@@ -0,0 +1,174 @@
+// Package trace provides distributed tracing, propagating the trace between services
+// using the W3C trace context headers (traceparent and tracestate).
+//
+// a Tracer creates spans which form a tree within a trace, once a span has ended it is
+// handed to an Exporter. Spans are carried between functions using a context.
+package trace
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Span represents a single operation within a trace.
+type Span struct {
+	mu sync.Mutex
+
+	Name       string            `json:"name"`                     // Name the name of the operation.
+	Context    SpanContext       `json:"-"`                        // Context the propagated portion of the span.
+	TraceID    string            `json:"trace_id"`                 // TraceID the hex encoded trace id.
+	SpanID     string            `json:"span_id"`                  // SpanID the hex encoded span id.
+	ParentID   string            `json:"parent_span_id,omitempty"` // ParentID the hex encoded parent span id, if any.
+	Start      time.Time         `json:"start"`                    // Start the time the span started.
+	End        time.Time         `json:"end"`                      // End the time the span ended.
+	Duration   time.Duration     `json:"duration"`                 // Duration the duration of the span.
+	Attributes map[string]string `json:"attributes,omitempty"`     // Attributes describe the operation.
+	Error      string            `json:"error,omitempty"`          // Error the error the operation failed with.
+
+	tracer *Tracer // tracer the tracer which started the span.
+	ended  bool    // ended whether the span has already ended.
+}
+
+// SetAttribute sets an attribute describing the operation.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+	s.Attributes[key] = value
+}
+
+// SetError records the error which the operation failed with.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Error = err.Error()
+}
+
+// Finish ends the span and exports it if the trace is sampled.
+// finishing a span more than once has no effect.
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.End = now()
+	s.Duration = s.End.Sub(s.Start)
+	s.mu.Unlock()
+
+	if s.Context.Sampled {
+		s.tracer.Exporter().Export(s)
+	}
+}
+
+// Tracer creates spans and exports them once finished.
+type Tracer struct {
+	mu       sync.RWMutex
+	exporter Exporter // exporter the exporter finished spans are handed to.
+}
+
+// Exporter retrieves the exporter finished spans are handed to.
+func (t *Tracer) Exporter() Exporter {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.exporter
+}
+
+// SetExporter replaces the exporter finished spans are handed to, this allows
+// the exporter of the default tracer to be configured at start up.
+func (t *Tracer) SetExporter(e Exporter) {
+	if e == nil {
+		e = Discard()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.exporter = e
+}
+
+// Start starts a new span as a child of the span assigned to ctx, or of the remote span
+// context assigned using ContextWithRemote. A new trace is started if ctx has neither.
+// the returned context has the new span assigned.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	s := &Span{Name: name, Start: now(), tracer: t}
+
+	parent, ok := parentContext(ctx)
+	if ok {
+		s.Context = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled, State: parent.State}
+		s.ParentID = parent.SpanID.String()
+	} else {
+		s.Context = SpanContext{TraceID: newTraceID(), Sampled: true}
+	}
+
+	s.Context.SpanID = newSpanID()
+	s.TraceID, s.SpanID = s.Context.TraceID.String(), s.Context.SpanID.String()
+	return ContextWithSpan(ctx, s), s
+}
+
+// New initialises a new tracer which exports finished spans to the supplied exporter.
+func New(e Exporter) *Tracer {
+	if e == nil {
+		e = Discard()
+	}
+	return &Tracer{exporter: e}
+}
+
+// defaultTracer the tracer shared by the server and the API clients it uses.
+var defaultTracer = New(Discard())
+
+// Default retrieves the default tracer, which discards every span until an
+// exporter is assigned using SetExporter.
+func Default() *Tracer { return defaultTracer }
+
+// now the function used to retrieve the current time, this can be overridden in tests.
+var now = time.Now
+
+// spanContextKey the context key to use for the current span.
+type spanContextKey struct{}
+
+// remoteContextKey the context key to use for a remote span context.
+type remoteContextKey struct{}
+
+// ContextWithSpan assigns the span to the context.
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, s)
+}
+
+// SpanFromContext retrieves the span assigned to the context, nil is returned if
+// there is no span assigned.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanContextKey{}).(*Span)
+	return s
+}
+
+// ContextWithRemote assigns a span context received from another service to the context
+// spans started from the context will be children of the remote span.
+func ContextWithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteContextKey{}, sc)
+}
+
+// parentContext retrieves the span context of the parent for a new span.
+func parentContext(ctx context.Context) (SpanContext, bool) {
+	if s := SpanFromContext(ctx); s != nil {
+		return s.Context, true
+	}
+
+	sc, ok := ctx.Value(remoteContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
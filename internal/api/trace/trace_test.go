@@ -0,0 +1,90 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracer_Start(t *testing.T) {
+	m := NewMemory()
+	tr := New(m)
+
+	ctx, root := tr.Start(context.Background(), "root")
+	_, child := tr.Start(ctx, "child")
+	child.SetAttribute("key", "value")
+	child.SetError(errors.New("failed"))
+	child.Finish()
+	root.Finish()
+	root.Finish() // finishing more than once has no effect.
+
+	spans := m.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, root.TraceID, spans[0].TraceID)
+	assert.Equal(t, root.SpanID, spans[0].ParentID)
+	assert.Equal(t, "value", spans[0].Attributes["key"])
+	assert.Equal(t, "failed", spans[0].Error)
+	assert.Empty(t, spans[1].ParentID)
+	assert.Equal(t, root, SpanFromContext(ctx))
+
+	m.Reset()
+	assert.Empty(t, m.Spans())
+}
+
+func TestTracer_StartRemote(t *testing.T) {
+	m := NewMemory()
+	tr := New(m)
+
+	sc, err := ParseTraceparent(testTraceparent)
+	require.NoError(t, err)
+
+	_, s := tr.Start(ContextWithRemote(context.Background(), sc), "remote")
+	s.Finish()
+	assert.Equal(t, testTraceID, s.TraceID)
+	assert.Equal(t, testSpanID, s.ParentID)
+	assert.NotEqual(t, testSpanID, s.SpanID)
+	assert.Len(t, m.Spans(), 1)
+
+	// a trace which is not sampled is not exported.
+	sc.Sampled = false
+	_, s = tr.Start(ContextWithRemote(context.Background(), sc), "remote")
+	s.Finish()
+	assert.Len(t, m.Spans(), 1)
+}
+
+func TestTracer_SetExporter(t *testing.T) {
+	tr := New(nil)
+	_, s := tr.Start(context.Background(), "discarded")
+	s.Finish()
+
+	m := NewMemory()
+	tr.SetExporter(m)
+	_, s = tr.Start(context.Background(), "exported")
+	s.Finish()
+	require.Len(t, m.Spans(), 1)
+	assert.Equal(t, "exported", m.Spans()[0].Name)
+}
+
+func TestJSON(t *testing.T) {
+	defer func(fn func() time.Time) { now = fn }(now)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+
+	b := &bytes.Buffer{}
+	_, s := New(JSON(b)).Start(context.Background(), "span")
+	now = func() time.Time { return start.Add(time.Second) }
+	s.Finish()
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &out))
+	assert.Equal(t, "span", out["name"])
+	assert.Equal(t, s.TraceID, out["trace_id"])
+	assert.Equal(t, float64(time.Second), out["duration"])
+}
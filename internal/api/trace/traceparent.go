@@ -0,0 +1,150 @@
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceparentHeader the W3C header which carries the trace and parent span id.
+	TraceparentHeader = "traceparent"
+	// TracestateHeader the W3C header which carries vendor specific trace state.
+	TracestateHeader = "tracestate"
+
+	traceparentVersion = "00" // traceparentVersion the only version of the traceparent header defined.
+	flagSampled        = 0x01 // flagSampled the trace flag which represents the trace is sampled.
+	invalidVersion     = "ff" // invalidVersion the version which is forbidden by the specification.
+	traceparentLength  = 55   // traceparentLength the length of a version 00 traceparent header.
+	maxTracestate      = 512  // maxTracestate the maximum length of tracestate we propagate.
+	traceparentSep     = "-"  // traceparentSep the separator between each traceparent field.
+)
+
+// TraceID a 16 byte trace identifier.
+type TraceID [16]byte
+
+// String returns the lowercase hex encoding of the trace id.
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid reports whether the trace id is not all zeros.
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// SpanID an 8 byte span identifier.
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding of the span id.
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid reports whether the span id is not all zeros.
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext the portion of a span which is propagated between services.
+type SpanContext struct {
+	TraceID TraceID // TraceID the id of the trace the span belongs to.
+	SpanID  SpanID  // SpanID the id of the span.
+	Sampled bool    // Sampled whether the trace is sampled and should be exported.
+	State   string  // State the vendor specific tracestate, which is propagated unchanged.
+}
+
+// IsValid reports whether both the trace and span ids are valid.
+func (sc SpanContext) IsValid() bool { return sc.TraceID.IsValid() && sc.SpanID.IsValid() }
+
+// Traceparent formats the span context as a version 00 traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return strings.Join([]string{traceparentVersion, sc.TraceID.String(), sc.SpanID.String(), flags}, traceparentSep)
+}
+
+// ParseTraceparent parses a traceparent header value into a span context.
+//
+// future versions of the header are accepted as long as the first four fields
+// are in the version 00 format, as defined in the specification.
+func ParseTraceparent(v string) (SpanContext, error) {
+	var sc SpanContext
+	v = strings.TrimSpace(v)
+	parts := strings.Split(v, traceparentSep)
+	if len(parts) < 4 || len(v) < traceparentLength {
+		return sc, errors.New("invalid traceparent: " + v)
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == invalidVersion || !isLowerHex(version) {
+		return sc, errors.New("invalid traceparent version: " + version)
+	}
+
+	if version == traceparentVersion && (len(parts) != 4 || len(v) != traceparentLength) {
+		return sc, errors.New("invalid traceparent: " + v)
+	}
+
+	if len(traceID) != 32 || !isLowerHex(traceID) || len(spanID) != 16 || !isLowerHex(spanID) ||
+		len(flags) != 2 || !isLowerHex(flags) {
+		return sc, errors.New("invalid traceparent: " + v)
+	}
+
+	hex.Decode(sc.TraceID[:], []byte(traceID))
+	hex.Decode(sc.SpanID[:], []byte(spanID))
+	if !sc.IsValid() {
+		return SpanContext{}, errors.New("invalid traceparent, ids cannot be all zeros: " + v)
+	}
+
+	f, _ := hex.DecodeString(flags)
+	sc.Sampled = f[0]&flagSampled == flagSampled
+	return sc, nil
+}
+
+// Extract retrieves the remote span context from the supplied headers.
+// false is returned if there is no valid traceparent header.
+func Extract(h http.Header) (SpanContext, bool) {
+	sc, err := ParseTraceparent(h.Get(TraceparentHeader))
+	if err != nil {
+		return SpanContext{}, false
+	}
+
+	if st := strings.TrimSpace(h.Get(TracestateHeader)); len(st) <= maxTracestate {
+		sc.State = st
+	}
+	return sc, true
+}
+
+// Inject sets the traceparent and tracestate headers from the supplied span context.
+func Inject(sc SpanContext, h http.Header) {
+	if !sc.IsValid() {
+		return
+	}
+
+	h.Set(TraceparentHeader, sc.Traceparent())
+	if sc.State != "" {
+		h.Set(TracestateHeader, sc.State)
+	}
+}
+
+// isLowerHex reports whether s only contains lowercase hex characters.
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// newTraceID generates a new random trace id.
+func newTraceID() (t TraceID) {
+	for !t.IsValid() {
+		rand.Read(t[:])
+	}
+	return
+}
+
+// newSpanID generates a new random span id.
+func newSpanID() (s SpanID) {
+	for !s.IsValid() {
+		rand.Read(s[:])
+	}
+	return
+}
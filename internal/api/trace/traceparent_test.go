@@ -0,0 +1,123 @@
+package trace
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID      = "00f067aa0ba902b7"
+	testTraceparent = "00-" + testTraceID + "-" + testSpanID + "-01"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Value    string
+		Expected func(t *testing.T, sc SpanContext, err error)
+	}{
+		{
+			Name:  "Sampled",
+			Value: testTraceparent,
+			Expected: func(t *testing.T, sc SpanContext, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, testTraceID, sc.TraceID.String())
+				assert.Equal(t, testSpanID, sc.SpanID.String())
+				assert.True(t, sc.Sampled)
+				assert.Equal(t, testTraceparent, sc.Traceparent())
+			},
+		},
+		{
+			Name:  "NotSampled",
+			Value: "00-" + testTraceID + "-" + testSpanID + "-00",
+			Expected: func(t *testing.T, sc SpanContext, err error) {
+				require.NoError(t, err)
+				assert.False(t, sc.Sampled)
+			},
+		},
+		{
+			Name:  "FutureVersion",
+			Value: "cc-" + testTraceID + "-" + testSpanID + "-01-what-the-future-holds",
+			Expected: func(t *testing.T, sc SpanContext, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, testTraceID, sc.TraceID.String())
+			},
+		},
+		{
+			Name:  "InvalidVersion",
+			Value: "ff-" + testTraceID + "-" + testSpanID + "-01",
+			Expected: func(t *testing.T, _ SpanContext, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			Name:  "ExtraFieldsVersion00",
+			Value: testTraceparent + "-00",
+			Expected: func(t *testing.T, _ SpanContext, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			Name:  "UpperCase",
+			Value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + testSpanID + "-01",
+			Expected: func(t *testing.T, _ SpanContext, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			Name:  "ZeroTraceID",
+			Value: "00-00000000000000000000000000000000-" + testSpanID + "-01",
+			Expected: func(t *testing.T, _ SpanContext, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			Name:  "ZeroSpanID",
+			Value: "00-" + testTraceID + "-0000000000000000-01",
+			Expected: func(t *testing.T, _ SpanContext, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			Name:  "Empty",
+			Value: "",
+			Expected: func(t *testing.T, _ SpanContext, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			sc, err := ParseTraceparent(tc.Value)
+			tc.Expected(t, sc, err)
+		})
+	}
+}
+
+func TestExtractInject(t *testing.T) {
+	h := make(http.Header)
+	h.Set(TraceparentHeader, testTraceparent)
+	h.Set(TracestateHeader, "congo=t61rcWkgMzE")
+
+	sc, ok := Extract(h)
+	require.True(t, ok)
+	assert.Equal(t, "congo=t61rcWkgMzE", sc.State)
+
+	out := make(http.Header)
+	Inject(sc, out)
+	assert.Equal(t, testTraceparent, out.Get(TraceparentHeader))
+	assert.Equal(t, "congo=t61rcWkgMzE", out.Get(TracestateHeader))
+
+	// an invalid span context is never injected.
+	out = make(http.Header)
+	Inject(SpanContext{}, out)
+	assert.Empty(t, out.Get(TraceparentHeader))
+
+	_, ok = Extract(make(http.Header))
+	assert.False(t, ok)
+}
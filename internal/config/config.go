@@ -0,0 +1,294 @@
+// Package config provides the configuration of the serve command, which is layered so that each
+// source overrides the one before it: the defaults, a YAML, JSON or TOML file, the POKEAPI_*
+// environment variables and finally the command line flags.
+//
+// the key of each value is the dotted path of its name in the configuration file, i.e
+// server.cache.ttl, which is overridden by the environment variable POKEAPI_SERVER_CACHE_TTL.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/backoff"
+	"github.com/jacklaaa89/pokeapi/internal/api/endpoint"
+	logfmt "github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/api/opts"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+)
+
+// the supported trace exporters.
+const (
+	TraceExporterNone   = "none"
+	TraceExporterStdout = "stdout"
+)
+
+// the supported translation backends.
+const (
+	TranslatorFunTranslations = "funtranslations"
+	TranslatorOffline         = "offline"
+)
+
+// the supported backoff strategies.
+const (
+	BackoffNone        = "none"
+	BackoffConstant    = "constant"
+	BackoffExponential = "exponential"
+)
+
+// Config the configuration of the server and each of the upstream API clients.
+type Config struct {
+	// Server the configuration of the HTTP server.
+	Server Server `yaml:"server" json:"server" toml:"server"`
+	// PokeAPI the configuration of the PokeAPI client.
+	PokeAPI Client `yaml:"pokeapi" json:"pokeapi" toml:"pokeapi"`
+	// Translation the configuration of the translation backends and the fun-translations client.
+	Translation Translation `yaml:"translation" json:"translation" toml:"translation"`
+}
+
+// Server the configuration of the HTTP server.
+type Server struct {
+	// Port the port to listen on.
+	Port int `yaml:"port" json:"port" toml:"port"`
+	// LogLevel the logging level, from 0 (None) to 4 (Debug).
+	LogLevel int `yaml:"log_level" json:"log_level" toml:"log_level"`
+	// TraceExporter the exporter to send trace spans to, either none or stdout.
+	TraceExporter string `yaml:"trace_exporter" json:"trace_exporter" toml:"trace_exporter"`
+	// TrustedNetworks the networks, in CIDR notation, whose X-Request-ID header is honoured.
+	TrustedNetworks []string `yaml:"trusted_networks" json:"trusted_networks" toml:"trusted_networks"`
+	// CacheControl the Cache-Control header to send with successful responses as route=directives,
+	// the directives contain commas so the values of the environment variable are separated using ;.
+	CacheControl []string `yaml:"cache_control" json:"cache_control" toml:"cache_control" sep:";"`
+	// Cache the configuration of the response cache in front of the pokemon handlers.
+	Cache Cache `yaml:"cache" json:"cache" toml:"cache"`
+}
+
+// Cache the configuration of the response cache, see middleware.CacheOptions.
+type Cache struct {
+	// TTL the duration a cached pokemon response is fresh for.
+	TTL Duration `yaml:"ttl" json:"ttl" toml:"ttl"`
+	// StaleWhileRevalidate the duration a stale response is served while it is revalidated in the background.
+	StaleWhileRevalidate Duration `yaml:"stale_while_revalidate" json:"stale_while_revalidate" toml:"stale_while_revalidate"`
+	// StaleIfError the duration a stale response is served when an upstream API fails.
+	StaleIfError Duration `yaml:"stale_if_error" json:"stale_if_error" toml:"stale_if_error"`
+	// MaxEntries the maximum amount of responses which are cached, the default is used if zero.
+	MaxEntries int `yaml:"max_entries" json:"max_entries" toml:"max_entries"`
+}
+
+// Client the configuration of an upstream API client, see opts.Options.
+type Client struct {
+	// Endpoints the endpoints of the API to fail over between in order, the public API is used if empty.
+	Endpoints []string `yaml:"endpoints" json:"endpoints" toml:"endpoints"`
+	// Timeout the timeout applied to each request, zero applies no timeout.
+	Timeout Duration `yaml:"timeout" json:"timeout" toml:"timeout"`
+	// MaxRetries the maximum amount of retries on a failure.
+	MaxRetries int64 `yaml:"max_retries" json:"max_retries" toml:"max_retries"`
+	// Backoff the backoff applied between retries.
+	Backoff Backoff `yaml:"backoff" json:"backoff" toml:"backoff"`
+	// Language the language sent in the Accept-Language header, i.e en-GB.
+	Language string `yaml:"language" json:"language" toml:"language"`
+}
+
+// Backoff the configuration of the backoff applied between retries.
+type Backoff struct {
+	// Strategy the backoff strategy, either none, constant or exponential.
+	Strategy string `yaml:"strategy" json:"strategy" toml:"strategy"`
+	// Initial the delay of the constant strategy, or the initial delay of the exponential strategy.
+	Initial Duration `yaml:"initial" json:"initial" toml:"initial"`
+	// Max the maximum delay of the exponential strategy.
+	Max Duration `yaml:"max" json:"max" toml:"max"`
+}
+
+// Translation the configuration of the translation backends and the fun-translations client.
+type Translation struct {
+	// Client the configuration of the fun-translations client.
+	Client `yaml:",inline"`
+
+	// APIKey the fun-translations API key, the key is read from APIKeyFile if defined.
+	APIKey Secret `yaml:"api_key" json:"api_key" toml:"api_key"`
+	// APIKeyFile the path to a file containing the fun-translations API key, i.e a mounted secret.
+	APIKeyFile string `yaml:"api_key_file" json:"api_key_file" toml:"api_key_file"`
+	// Methods the translation methods offered by the translation API to register in addition
+	// to shakespeare and yoda, i.e pirate.
+	Methods []string `yaml:"methods" json:"methods" toml:"methods"`
+	// Translators the translation backends to try in order until one succeeds.
+	Translators []string `yaml:"translators" json:"translators" toml:"translators"`
+	// Rules the path to the translation rules, the default rules are used if empty.
+	Rules string `yaml:"rules" json:"rules" toml:"rules"`
+	// Memory the path to the translation memory consulted before calling fun-translations, if any.
+	Memory string `yaml:"memory" json:"memory" toml:"memory"`
+}
+
+// Default the default configuration.
+func Default() *Config {
+	client := Client{
+		Endpoints:  []string{},
+		MaxRetries: 2,
+		Backoff:    Backoff{Strategy: BackoffNone},
+		Language:   language.BritishEnglish.String(),
+	}
+
+	return &Config{
+		Server: Server{
+			Port:            5555,
+			LogLevel:        int(logfmt.LevelError),
+			TraceExporter:   TraceExporterNone,
+			TrustedNetworks: []string{},
+			// species data rarely changes.
+			CacheControl: []string{
+				"/pokemon/{name}=public, max-age=86400",
+				"/pokemon/{name}/translated=public, max-age=3600",
+			},
+			Cache: Cache{
+				TTL:                  Duration(5 * time.Minute),
+				StaleWhileRevalidate: Duration(time.Minute),
+				StaleIfError:         Duration(24 * time.Hour),
+			},
+		},
+		PokeAPI: client,
+		Translation: Translation{
+			Client:      client,
+			Methods:     []string{},
+			Translators: []string{TranslatorFunTranslations},
+		},
+	}
+}
+
+// Validate validates the configuration, each invalid value is reported.
+//
+// the translation methods and rules are not validated, as the methods are registered
+// globally and the rules reference them, see translation.RegisterAll and rules.Load.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(key string, err error) {
+		if err != nil {
+			problems = append(problems, key+": "+err.Error())
+		}
+	}
+
+	s := c.Server
+	if s.Port < 1 || s.Port > 65535 {
+		add("server.port", fmt.Errorf("must be between 1 and 65535, got %d", s.Port))
+	}
+	if s.LogLevel < int(logfmt.LevelNone) || s.LogLevel > int(logfmt.LevelDebug) {
+		add("server.log_level", fmt.Errorf("must be between 0 and 4, got %d", s.LogLevel))
+	}
+	if !contains([]string{TraceExporterNone, TraceExporterStdout}, s.TraceExporter) {
+		add("server.trace_exporter", fmt.Errorf("unsupported trace exporter: %s", s.TraceExporter))
+	}
+	for _, n := range s.TrustedNetworks {
+		_, _, err := net.ParseCIDR(n)
+		add("server.trusted_networks", err)
+	}
+	_, err := middleware.ParseCacheControl(s.CacheControl)
+	add("server.cache_control", err)
+	add("server.cache.ttl", nonNegative(s.Cache.TTL))
+	add("server.cache.stale_while_revalidate", nonNegative(s.Cache.StaleWhileRevalidate))
+	add("server.cache.stale_if_error", nonNegative(s.Cache.StaleIfError))
+
+	add("pokeapi", c.PokeAPI.validate())
+	add("translation", c.Translation.validate())
+
+	if len(c.Translation.Translators) == 0 {
+		add("translation.translators", errors.New("at least one translator is required"))
+	}
+	for _, t := range c.Translation.Translators {
+		if !contains([]string{TranslatorFunTranslations, TranslatorOffline}, t) {
+			add("translation.translators", fmt.Errorf("unsupported translator: %s", t))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n\t%s", strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
+
+// validate validates the client configuration.
+func (c Client) validate() error {
+	if len(c.Endpoints) > 0 {
+		if _, err := endpoint.Failover(c.Endpoints...); err != nil {
+			return fmt.Errorf("endpoints: %w", err)
+		}
+	}
+	if err := nonNegative(c.Timeout); err != nil {
+		return fmt.Errorf("timeout: %w", err)
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries: cannot be negative, got %d", c.MaxRetries)
+	}
+
+	_, err := c.Options()
+	return err
+}
+
+// Options the options used to initialise the client, the endpoints are failed over between when
+// more than one endpoint is defined.
+func (c Client) Options() ([]opts.APIOption, error) {
+	b, err := c.Backoff.backoff()
+	if err != nil {
+		return nil, fmt.Errorf("backoff: %w", err)
+	}
+
+	o := []opts.APIOption{
+		opts.WithTimeout(time.Duration(c.Timeout)),
+		opts.WithMaxNetworkRetries(c.MaxRetries),
+		opts.WithBackoff(b),
+	}
+
+	if c.Language != "" {
+		l, err := language.Parse(c.Language)
+		if err != nil {
+			return nil, fmt.Errorf("language: %w", err)
+		}
+		o = append(o, opts.WithLanguage(l))
+	}
+
+	if len(c.Endpoints) > 1 {
+		p, err := endpoint.Failover(c.Endpoints...)
+		if err != nil {
+			return nil, fmt.Errorf("endpoints: %w", err)
+		}
+		o = append(o, opts.WithEndpoints(p))
+	}
+	return o, nil
+}
+
+// backoff initialises the backoff defined by the strategy.
+func (b Backoff) backoff() (backoff.Backoff, error) {
+	switch b.Strategy {
+	case BackoffNone, "":
+		return backoff.Zero(), nil
+	case BackoffConstant:
+		if err := nonNegative(b.Initial); err != nil {
+			return nil, fmt.Errorf("initial: %w", err)
+		}
+		return backoff.Constant(time.Duration(b.Initial)), nil
+	case BackoffExponential:
+		return backoff.Exponential(time.Duration(b.Initial), time.Duration(b.Max))
+	}
+	return nil, fmt.Errorf("unsupported backoff strategy: %s", b.Strategy)
+}
+
+// nonNegative validates that the duration is not negative.
+func nonNegative(d Duration) error {
+	if d < 0 {
+		return fmt.Errorf("cannot be negative, got %s", time.Duration(d))
+	}
+	return nil
+}
+
+// contains determines whether s contains v.
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
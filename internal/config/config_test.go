@@ -0,0 +1,182 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	c := Default()
+	require.NoError(t, c.Validate())
+
+	assert.Equal(t, 5555, c.Server.Port)
+	assert.Equal(t, Duration(5*time.Minute), c.Server.Cache.TTL)
+	assert.Equal(t, []string{TranslatorFunTranslations}, c.Translation.Translators)
+	assert.Equal(t, int64(2), c.PokeAPI.MaxRetries)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Setup    func(c *Config)
+		Expected func(t *testing.T, err error)
+	}{
+		{
+			Name:  "Valid",
+			Setup: func(c *Config) {},
+			Expected: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			Name: "ValidClients",
+			Setup: func(c *Config) {
+				c.PokeAPI.Endpoints = []string{"http://localhost:8080/api/v2", "https://pokeapi.co/api/v2"}
+				c.PokeAPI.Backoff = Backoff{Strategy: BackoffExponential, Initial: Duration(time.Millisecond), Max: Duration(time.Second)}
+				c.Translation.Backoff = Backoff{Strategy: BackoffConstant, Initial: Duration(time.Second)}
+				c.Translation.Translators = []string{TranslatorFunTranslations, TranslatorOffline}
+			},
+			Expected: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			Name: "InvalidServer",
+			Setup: func(c *Config) {
+				c.Server.Port = 0
+				c.Server.LogLevel = 5
+				c.Server.TraceExporter = "jaeger"
+				c.Server.TrustedNetworks = []string{"10.0.0.1"}
+				c.Server.CacheControl = []string{"public"}
+				c.Server.Cache.TTL = Duration(-time.Second)
+			},
+			Expected: func(t *testing.T, err error) {
+				require.Error(t, err)
+				for _, key := range []string{
+					"server.port", "server.log_level", "server.trace_exporter",
+					"server.trusted_networks", "server.cache_control", "server.cache.ttl",
+				} {
+					assert.Contains(t, err.Error(), key+":")
+				}
+			},
+		},
+		{
+			Name: "InvalidEndpoint",
+			Setup: func(c *Config) {
+				c.PokeAPI.Endpoints = []string{"localhost"}
+			},
+			Expected: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "pokeapi: endpoints")
+			},
+		},
+		{
+			Name: "InvalidBackoff",
+			Setup: func(c *Config) {
+				c.Translation.Backoff = Backoff{Strategy: BackoffExponential}
+			},
+			Expected: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "translation: backoff")
+			},
+		},
+		{
+			Name: "UnsupportedBackoff",
+			Setup: func(c *Config) {
+				c.PokeAPI.Backoff.Strategy = "linear"
+			},
+			Expected: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unsupported backoff strategy: linear")
+			},
+		},
+		{
+			Name: "InvalidLanguage",
+			Setup: func(c *Config) {
+				c.PokeAPI.Language = "not a language"
+			},
+			Expected: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "pokeapi: language")
+			},
+		},
+		{
+			Name: "NegativeRetries",
+			Setup: func(c *Config) {
+				c.Translation.MaxRetries = -1
+			},
+			Expected: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "translation: max_retries")
+			},
+		},
+		{
+			Name: "NoTranslators",
+			Setup: func(c *Config) {
+				c.Translation.Translators = nil
+			},
+			Expected: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "at least one translator is required")
+			},
+		},
+		{
+			Name: "UnsupportedTranslator",
+			Setup: func(c *Config) {
+				c.Translation.Translators = []string{"google"}
+			},
+			Expected: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unsupported translator: google")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			c := Default()
+			tc.Setup(c)
+			tc.Expected(t, c.Validate())
+		})
+	}
+}
+
+func TestClient_Options(t *testing.T) {
+	c := Default().PokeAPI
+	o, err := c.Options()
+	require.NoError(t, err)
+	assert.Len(t, o, 4)
+
+	// an endpoint pool is only used when there is more than one endpoint.
+	c.Endpoints = []string{"http://localhost:8080", "https://pokeapi.co/api/v2"}
+	o, err = c.Options()
+	require.NoError(t, err)
+	assert.Len(t, o, 5)
+}
+
+func TestSecret(t *testing.T) {
+	s := Secret("key")
+	assert.Equal(t, mask, s.String())
+	assert.Equal(t, "key", string(s))
+
+	b, err := s.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, mask, string(b))
+
+	assert.Empty(t, Secret("").String())
+}
+
+func TestDuration(t *testing.T) {
+	var d Duration
+	require.NoError(t, d.UnmarshalText([]byte("1m30s")))
+	assert.Equal(t, Duration(90*time.Second), d)
+
+	b, err := d.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "1m30s", string(b))
+
+	assert.Error(t, d.UnmarshalText([]byte("soon")))
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix the prefix of the environment variables which override the configuration file.
+const EnvPrefix = "POKEAPI_"
+
+// EnvFile the environment variable which defines the path to the configuration file.
+const EnvFile = EnvPrefix + "CONFIG"
+
+// the supported configuration file formats.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// LookupFunc retrieves the value of an environment variable, i.e os.LookupEnv.
+type LookupFunc func(key string) (string, bool)
+
+// Load loads the configuration, the file at path, if defined, overrides the defaults and is
+// overridden by the environment variables retrieved using env, if supplied. The format of the
+// file is determined by its extension and unknown keys are rejected.
+func Load(path string, env LookupFunc) (*Config, error) {
+	c := Default()
+	if path != "" {
+		if err := c.load(path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if env != nil {
+		if err := c.env(env); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// load decodes the file at path over the configuration.
+func (c *Config) load(path string) error {
+	format, err := formatOf(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.decode(f, format)
+}
+
+// formatOf determines the format of the file at path from its extension.
+func formatOf(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	}
+	return "", errors.New("unsupported configuration format, expected .yaml, .yml, .json or .toml")
+}
+
+// decode decodes the configuration in format from r, unknown keys are rejected.
+func (c *Config) decode(r io.Reader, format string) error {
+	switch format {
+	case FormatYAML:
+		d := yaml.NewDecoder(r)
+		d.KnownFields(true)
+		// an empty file leaves the defaults.
+		if err := d.Decode(c); err != nil && err != io.EOF {
+			return err
+		}
+		return nil
+	case FormatJSON:
+		d := json.NewDecoder(r)
+		d.DisallowUnknownFields()
+		return d.Decode(c)
+	case FormatTOML:
+		md, err := toml.NewDecoder(r).Decode(c)
+		if err != nil {
+			return err
+		}
+		if keys := md.Undecoded(); len(keys) > 0 {
+			return fmt.Errorf("unknown keys: %v", keys)
+		}
+		return nil
+	}
+	return errors.New("unsupported configuration format: " + format)
+}
+
+// Write writes the configuration to w in format, secrets are masked.
+func (c *Config) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatYAML:
+		e := yaml.NewEncoder(w)
+		e.SetIndent(2)
+		if err := e.Encode(c); err != nil {
+			return err
+		}
+		return e.Close()
+	case FormatJSON:
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(c)
+	case FormatTOML:
+		return toml.NewEncoder(w).Encode(c)
+	}
+	return errors.New("unsupported configuration format: " + format)
+}
+
+// Set sets the value defined by key, i.e server.cache.ttl, from its string form. A list is
+// replaced by values, every other value expects a single value.
+func (c *Config) Set(key string, values ...string) error {
+	for _, f := range fields(reflect.ValueOf(c).Elem(), "") {
+		if f.key == key {
+			return f.set(values)
+		}
+	}
+	return errors.New("unknown configuration key: " + key)
+}
+
+// Env the environment variable which overrides the value defined by key, i.e
+// server.cache.ttl is overridden by POKEAPI_SERVER_CACHE_TTL.
+func Env(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// env overrides each value defined in the environment, the values of a list are separated
+// using a comma unless the field defines another separator.
+func (c *Config) env(lookup LookupFunc) error {
+	for _, f := range fields(reflect.ValueOf(c).Elem(), "") {
+		v, ok := lookup(Env(f.key))
+		if !ok {
+			continue
+		}
+
+		values := []string{v}
+		if f.value.Kind() == reflect.Slice {
+			values = strings.Split(v, f.sep)
+		}
+		if err := f.set(values); err != nil {
+			return fmt.Errorf("%s: %w", Env(f.key), err)
+		}
+	}
+	return nil
+}
+
+// field a single value of the configuration.
+type field struct {
+	key   string        // key the dotted path of the value, i.e server.cache.ttl.
+	sep   string        // sep separates the values of a list defined in the environment.
+	value reflect.Value // value the settable value.
+}
+
+// fields retrieves each value of the struct v, keyed using the YAML name of each field
+// prefixed by the key of its parent.
+func fields(v reflect.Value, prefix string) []field {
+	var out []field
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+
+		key := prefix
+		if name != "" {
+			if key != "" {
+				key += "."
+			}
+			key += name
+		}
+
+		if sf.Type.Kind() == reflect.Struct {
+			out = append(out, fields(v.Field(i), key)...)
+			continue
+		}
+
+		sep := sf.Tag.Get("sep")
+		if sep == "" {
+			sep = ","
+		}
+		out = append(out, field{key: key, sep: sep, value: v.Field(i)})
+	}
+	return out
+}
+
+// set sets the value of the field from its string form.
+func (f field) set(values []string) error {
+	if f.value.Kind() == reflect.Slice {
+		s := make([]string, 0, len(values))
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				s = append(s, v)
+			}
+		}
+		f.value.Set(reflect.ValueOf(s))
+		return nil
+	}
+
+	if len(values) != 1 {
+		return fmt.Errorf("%s: expected a single value, got %d", f.key, len(values))
+	}
+
+	v := strings.TrimSpace(values[0])
+	if u, ok := f.value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(v))
+	}
+
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(v)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(i)
+	default:
+		return fmt.Errorf("%s: unsupported type %s", f.key, f.value.Type())
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// env a LookupFunc which retrieves the variables from a map.
+func env(vars map[string]string) LookupFunc {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+// write writes the contents to a file named name in a temporary directory.
+func write(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tt := []struct {
+		Name     string
+		File     string // File the name of the configuration file.
+		Contents string // Contents the contents of the configuration file.
+		Env      map[string]string
+		Expected func(t *testing.T, c *Config, err error)
+	}{
+		{
+			Name: "Defaults",
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, Default(), c)
+			},
+		},
+		{
+			Name: "YAML",
+			File: "config.yaml",
+			Contents: `
+server:
+  port: 8080
+  cache:
+    ttl: 1m
+pokeapi:
+  endpoints: [http://localhost:8080/api/v2]
+  timeout: 2s
+translation:
+  api_key: secret
+  backoff:
+    strategy: constant
+    initial: 500ms
+`,
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 8080, c.Server.Port)
+				assert.Equal(t, Duration(time.Minute), c.Server.Cache.TTL)
+				assert.Equal(t, []string{"http://localhost:8080/api/v2"}, c.PokeAPI.Endpoints)
+				assert.Equal(t, Duration(2*time.Second), c.PokeAPI.Timeout)
+				assert.Equal(t, Secret("secret"), c.Translation.APIKey)
+				assert.Equal(t, Backoff{Strategy: BackoffConstant, Initial: Duration(500 * time.Millisecond)}, c.Translation.Backoff)
+
+				// values not in the file keep the default.
+				assert.Equal(t, Duration(time.Minute), c.Server.Cache.StaleWhileRevalidate)
+				assert.Equal(t, Default().Server.CacheControl, c.Server.CacheControl)
+			},
+		},
+		{
+			Name:     "EmptyYAML",
+			File:     "config.yml",
+			Contents: "",
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, Default(), c)
+			},
+		},
+		{
+			Name:     "JSON",
+			File:     "config.json",
+			Contents: `{"server": {"port": 8080}, "translation": {"translators": ["offline"], "timeout": "3s"}}`,
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 8080, c.Server.Port)
+				assert.Equal(t, []string{TranslatorOffline}, c.Translation.Translators)
+				assert.Equal(t, Duration(3*time.Second), c.Translation.Timeout)
+			},
+		},
+		{
+			Name: "TOML",
+			File: "config.toml",
+			Contents: `
+[server]
+port = 8080
+trusted_networks = ["10.0.0.0/8"]
+
+[translation]
+methods = ["pirate"]
+max_retries = 5
+`,
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 8080, c.Server.Port)
+				assert.Equal(t, []string{"10.0.0.0/8"}, c.Server.TrustedNetworks)
+				assert.Equal(t, []string{"pirate"}, c.Translation.Methods)
+				assert.Equal(t, int64(5), c.Translation.MaxRetries)
+			},
+		},
+		{
+			Name:     "UnknownYAMLKey",
+			File:     "config.yaml",
+			Contents: "server:\n  prot: 8080\n",
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "prot")
+			},
+		},
+		{
+			Name:     "UnknownJSONKey",
+			File:     "config.json",
+			Contents: `{"server": {"prot": 8080}}`,
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "prot")
+			},
+		},
+		{
+			Name:     "UnknownTOMLKey",
+			File:     "config.toml",
+			Contents: "[server]\nprot = 8080\n",
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "server.prot")
+			},
+		},
+		{
+			Name:     "InvalidDuration",
+			File:     "config.yaml",
+			Contents: "server:\n  cache:\n    ttl: soon\n",
+			Expected: func(t *testing.T, c *Config, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			Name:     "UnsupportedFormat",
+			File:     "config.ini",
+			Contents: "port=8080",
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unsupported configuration format")
+			},
+		},
+		{
+			Name:     "EnvOverridesFile",
+			File:     "config.yaml",
+			Contents: "server:\n  port: 8080\n  log_level: 2\n",
+			Env: map[string]string{
+				"POKEAPI_SERVER_PORT":          "9090",
+				"POKEAPI_SERVER_CACHE_TTL":     "10m",
+				"POKEAPI_POKEAPI_ENDPOINTS":    "http://a.local, http://b.local",
+				"POKEAPI_SERVER_CACHE_CONTROL": "/pokemon/{name}=public, max-age=60;/pokemon/{name}/translated=no-store",
+				"POKEAPI_TRANSLATION_API_KEY":  "secret",
+				"TRANSLATION_API_KEY":          "ignored",
+			},
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 9090, c.Server.Port)
+				assert.Equal(t, 2, c.Server.LogLevel)
+				assert.Equal(t, Duration(10*time.Minute), c.Server.Cache.TTL)
+				assert.Equal(t, []string{"http://a.local", "http://b.local"}, c.PokeAPI.Endpoints)
+				assert.Equal(t, []string{
+					"/pokemon/{name}=public, max-age=60",
+					"/pokemon/{name}/translated=no-store",
+				}, c.Server.CacheControl)
+				assert.Equal(t, Secret("secret"), c.Translation.APIKey)
+			},
+		},
+		{
+			Name: "InvalidEnv",
+			Env:  map[string]string{"POKEAPI_SERVER_PORT": "http"},
+			Expected: func(t *testing.T, c *Config, err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "POKEAPI_SERVER_PORT")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			var path string
+			if tc.File != "" {
+				path = write(t, tc.File, tc.Contents)
+			}
+
+			c, err := Load(path, env(tc.Env))
+			tc.Expected(t, c, err)
+		})
+	}
+}
+
+func TestConfig_Set(t *testing.T) {
+	c := Default()
+	require.NoError(t, c.Set("server.port", "8080"))
+	require.NoError(t, c.Set("translation.timeout", "5s"))
+	require.NoError(t, c.Set("translation.translators", "offline", "funtranslations"))
+
+	assert.Equal(t, 8080, c.Server.Port)
+	assert.Equal(t, Duration(5*time.Second), c.Translation.Timeout)
+	assert.Equal(t, []string{"offline", "funtranslations"}, c.Translation.Translators)
+
+	assert.Error(t, c.Set("server.prot", "8080"))
+	assert.Error(t, c.Set("server.port", "1", "2"))
+	assert.Error(t, c.Set("server.port", "http"))
+}
+
+func TestEnv(t *testing.T) {
+	assert.Equal(t, "POKEAPI_SERVER_CACHE_TTL", Env("server.cache.ttl"))
+	assert.Equal(t, "POKEAPI_TRANSLATION_API_KEY", Env("translation.api_key"))
+}
+
+func TestConfig_Write(t *testing.T) {
+	for _, format := range []string{FormatYAML, FormatJSON, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			c := Default()
+			c.Server.Port = 8080
+			c.Translation.APIKey = "secret"
+
+			b := new(bytes.Buffer)
+			require.NoError(t, c.Write(b, format))
+			assert.Contains(t, b.String(), "8080")
+			assert.Contains(t, b.String(), mask)
+			assert.NotContains(t, b.String(), "secret")
+
+			// the written configuration can be loaded again, except for the masked secrets.
+			l, err := Load(write(t, "config."+format, b.String()), nil)
+			require.NoError(t, err)
+			l.Translation.APIKey = c.Translation.APIKey
+			assert.Equal(t, c, l)
+		})
+	}
+
+	assert.Error(t, Default().Write(new(bytes.Buffer), "ini"))
+}
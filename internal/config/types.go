@@ -0,0 +1,43 @@
+package config
+
+import "time"
+
+// mask the value a secret is replaced with when encoded.
+const mask = "********"
+
+// Duration a time.Duration which is defined as a string in each format, i.e 5m or 1h30m.
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) { return []byte(time.Duration(d).String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Secret a value which is masked when encoded or formatted, so that it is never
+// written out when the configuration is printed.
+type Secret string
+
+// String implements fmt.Stringer interface.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return mask
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s Secret) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (s *Secret) UnmarshalText(b []byte) error {
+	*s = Secret(b)
+	return nil
+}
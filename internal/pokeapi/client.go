@@ -28,6 +28,8 @@ type Client struct {
 }
 
 // NewWithEndpoint initialises a new pokeapi client with a defined endpoint and a set of options.
+// multiple endpoints to fail over between, i.e a self-hosted mirror and the public API, can
+// be defined by supplying opts.WithEndpoints, which takes precedence over endpoint.
 func NewWithEndpoint(endpoint string, o ...opts.APIOption) *Client {
 	o = append(o, opts.WithEncoder(json.New()), opts.WithUserAgent(userAgent))
 	c := &Client{common: service{api.New(endpoint, o...)}}
@@ -6,9 +6,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jacklaaa89/pokeapi/internal/api/apitest/mock"
+	"github.com/jacklaaa89/pokeapi/internal/api/endpoint"
 	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
+	"github.com/jacklaaa89/pokeapi/internal/api/opts"
 )
 
 func TestNew(t *testing.T) {
@@ -44,3 +47,45 @@ func TestNewWithEndpoint(t *testing.T) {
 	assert.NoError(t, m.AllExpectationsMet())
 	assert.Equal(t, "Mewtwo", s.Name)
 }
+
+func TestPokemonService_Pokemon(t *testing.T) {
+	m := mock.NewMockAPI(json.New())
+	m.Expect("/pokemon/bulbasaur", http.MethodGet).WithResult(http.StatusOK, &Pokemon{
+		Name:  "bulbasaur",
+		Types: []*PokemonType{{Slot: 1, Type: &NamedAPIResource{Name: "grass"}}},
+	})
+
+	m.Start()
+	defer m.Close()
+
+	c := NewWithEndpoint(m.URL())
+	p, err := c.Pokemon.Pokemon(context.Background(), "bulbasaur")
+	require.NoError(t, err)
+	assert.NoError(t, m.AllExpectationsMet())
+	assert.Equal(t, []string{"grass"}, p.TypeNames())
+}
+
+func TestNewWithEndpoint_Failover(t *testing.T) {
+	species := &Species{Name: "Mewtwo", IsLegendary: true}
+
+	primary := mock.NewMockAPI(json.New())
+	primary.Expect("/pokemon-species/mewtwo", http.MethodGet).WithStatusCode(http.StatusServiceUnavailable)
+	primary.Start()
+	defer primary.Close()
+
+	secondary := mock.NewMockAPI(json.New())
+	secondary.Expect("/pokemon-species/mewtwo", http.MethodGet).WithResult(http.StatusOK, species)
+	secondary.Start()
+	defer secondary.Close()
+
+	p, err := endpoint.Failover(primary.URL(), secondary.URL())
+	require.NoError(t, err)
+
+	// the pool takes precedence over the supplied endpoint.
+	c := NewWithEndpoint(defaultEndpoint, opts.WithEndpoints(p))
+	s, err := c.Pokemon.Species(context.Background(), "mewtwo")
+	assert.NoError(t, err)
+	assert.Equal(t, "Mewtwo", s.Name)
+	assert.NoError(t, primary.AllExpectationsMet())
+	assert.NoError(t, secondary.AllExpectationsMet())
+}
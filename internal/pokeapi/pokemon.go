@@ -25,3 +25,14 @@ func (p *PokemonService) Species(ctx context.Context, reference string) (s *Spec
 	err = p.c.Call(ctx, http.MethodGet, path, nil, s)
 	return
 }
+
+// Pokemon retrieves a single pokemon variety, i.e its types. The reference can either be the id
+// or name of the pokemon to query, see Species.DefaultVariety for the default pokemon of a species.
+//
+// see: https://pokeapi.co/docs/v2#pokemon
+func (p *PokemonService) Pokemon(ctx context.Context, reference string) (pk *Pokemon, err error) {
+	pk = new(Pokemon)
+	path := api.FormatURLPath("/pokemon/%s/", reference)
+	err = p.c.Call(ctx, http.MethodGet, path, nil, pk)
+	return
+}
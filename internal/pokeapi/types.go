@@ -18,13 +18,58 @@ type FlavorText struct {
 	Language *NamedAPIResource `json:"language"`    // Language the language this name is in.
 }
 
+// Variety a Pokémon which is a variety of a species.
+type Variety struct {
+	IsDefault bool              `json:"is_default"` // IsDefault whether this variety is the default variety.
+	Pokemon   *NamedAPIResource `json:"pokemon"`    // Pokemon the Pokémon variety.
+}
+
 // Species represents the resource for a pokeapi pokemon.
 // see: https://pokeapi.co/docs/v2#pokemon-species for more information
 type Species struct {
 	Name        string            `json:"name"`                // Name the name for this resource.
 	IsLegendary bool              `json:"is_legendary"`        // IsLegendary whether or not this is a legendary Pokémon.
+	IsMythical  bool              `json:"is_mythical"`         // IsMythical whether or not this is a mythical Pokémon.
 	Habitat     *NamedAPIResource `json:"habitat"`             // Habitat habitat this Pokémon pokemon can be encountered in.
+	Color       *NamedAPIResource `json:"color"`               // Color the color of this Pokémon for Pokédex search.
+	Generation  *NamedAPIResource `json:"generation"`          // Generation the generation this Pokémon pokemon was introduced in.
 	FlavorText  []*FlavorText     `json:"flavor_text_entries"` // FlavorText a list of flavor text entries for this Pokémon pokemon.
+	Varieties   []*Variety        `json:"varieties"`           // Varieties a list of the Pokémon that exist within this Pokémon pokemon.
+}
+
+// DefaultVariety retrieves the name of the default Pokémon of the species, the name
+// of the species is returned if there is no default variety.
+func (s *Species) DefaultVariety() string {
+	for _, v := range s.Varieties {
+		if v.IsDefault && v.Pokemon != nil {
+			return v.Pokemon.Name
+		}
+	}
+	return s.Name
+}
+
+// PokemonType the type of a Pokémon.
+type PokemonType struct {
+	Slot int               `json:"slot"` // Slot the order the Pokémon's types are listed in.
+	Type *NamedAPIResource `json:"type"` // Type the type the referenced Pokémon has.
+}
+
+// Pokemon represents the resource for a single pokemon variety.
+// see: https://pokeapi.co/docs/v2#pokemon for more information
+type Pokemon struct {
+	Name  string         `json:"name"`  // Name the name for this resource.
+	Types []*PokemonType `json:"types"` // Types a list of details showing types this Pokémon has.
+}
+
+// TypeNames retrieves the names of the types of the Pokémon in slot order.
+func (p *Pokemon) TypeNames() []string {
+	types := make([]string, 0, len(p.Types))
+	for _, t := range p.Types {
+		if t.Type != nil {
+			types = append(types, t.Type.Name)
+		}
+	}
+	return types
 }
 
 // Description attempts to find the first description for the pokemon for the supplied language
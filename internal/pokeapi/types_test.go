@@ -103,3 +103,20 @@ func TestSpecies_Description(t *testing.T) {
 		})
 	}
 }
+
+func TestSpecies_DefaultVariety(t *testing.T) {
+	s := &Species{Name: "wormadam", Varieties: []*Variety{
+		{IsDefault: false, Pokemon: &NamedAPIResource{Name: "wormadam-sandy"}},
+		{IsDefault: true, Pokemon: &NamedAPIResource{Name: "wormadam-plant"}},
+	}}
+	assert.Equal(t, "wormadam-plant", s.DefaultVariety())
+	assert.Equal(t, "mewtwo", (&Species{Name: "mewtwo"}).DefaultVariety())
+}
+
+func TestPokemon_TypeNames(t *testing.T) {
+	p := &Pokemon{Types: []*PokemonType{
+		{Slot: 1, Type: &NamedAPIResource{Name: "grass"}},
+		{Slot: 2, Type: &NamedAPIResource{Name: "poison"}},
+	}}
+	assert.Equal(t, []string{"grass", "poison"}, p.TypeNames())
+}
@@ -2,28 +2,104 @@ package server
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/jacklaaa89/pokeapi/internal/api/format"
+	"github.com/jacklaaa89/pokeapi/internal/api/log"
+	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/api/metrics"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
+	"github.com/jacklaaa89/pokeapi/internal/server/helpers"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
 	"github.com/jacklaaa89/pokeapi/internal/server/pokemon"
+	"github.com/jacklaaa89/pokeapi/internal/server/problems"
+	"github.com/jacklaaa89/pokeapi/internal/server/rules"
 	"github.com/jacklaaa89/pokeapi/internal/server/status"
+	"github.com/jacklaaa89/pokeapi/internal/server/translations"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
 )
 
-// Handler defines the http.Handler to use with the server.
-func Handler(middleware ...mux.MiddlewareFunc) http.Handler {
+// Server serves the API using the dependencies it is initialised with, so that several
+// differently configured servers can run in the same process, i.e against mock APIs in tests.
+type Server struct {
+	species    pokemon.SpeciesSource
+	translator translation.Translator
+	rules      *rules.Engine
+	methods    translation.Registry
+	quota      func() translation.Quota
+	logger     log.Logger
+	encoders   map[string]format.Encoder
+	clock      middleware.Clock
+	metrics    *metrics.Prometheus
+	tracer     *trace.Tracer
+	trust      []middleware.Trust
+	middleware []mux.MiddlewareFunc
+}
+
+// New initialises a server which retrieves species from the species source, i.e the PokeAPI
+// pokemon service, and translates descriptions using the translator.
+func New(species pokemon.SpeciesSource, t translation.Translator, o ...Option) *Server {
+	s := &Server{
+		species:    species,
+		translator: t,
+		rules:      rules.Default(),
+		methods:    translation.Registered(),
+		quota:      func() translation.Quota { return translation.Quota{} },
+		logger:     fmt.New(fmt.LevelNone),
+		encoders:   helpers.DefaultEncoders(),
+		clock:      time.Now,
+		metrics:    metrics.NewPrometheus(),
+		tracer:     trace.New(trace.Discard()),
+	}
+
+	for _, opt := range o {
+		opt.apply(s)
+	}
+	return s
+}
+
+// Handler defines the http.Handler to use with the server, the routes are built from the
+// dependencies of the server. A request id, the logger, encoders, clock and the details used
+// to negotiate the response are assigned to each request, and the request is recorded in the
+// metrics registry served from /metrics and traced using the tracer, before the middleware
+// the server is initialised with is applied.
+func (s *Server) Handler() http.Handler {
 	m := mux.NewRouter()
 
-	m.Use(middleware...)
+	m.Use(
+		middleware.WithRequestID(s.trust...),
+		middleware.WithLogger(s.logger),
+		middleware.WithEncoders(s.encoders),
+		middleware.WithClock(s.clock),
+		middleware.WithMetrics(s.metrics),
+		middleware.WithNegotiation(),
+		middleware.WithTracing(s.tracer),
+	)
+	m.Use(s.middleware...)
+
+	p := pokemon.NewHandler(s.species, s.translator, s.rules, s.methods)
 
 	// === pokemon resource endpoints ===
-	m.HandleFunc("/pokemon/{name}", pokemon.Get).
+	m.HandleFunc("/pokemon/{name}", p.Get).
 		Methods(http.MethodGet)
-	m.HandleFunc("/pokemon/{name}/translated", pokemon.Translated).
+	m.HandleFunc("/pokemon/{name}/translated", p.Translated).
 		Methods(http.MethodGet)
 
 	// === miscellaneous resource endpoints ===
 	m.HandleFunc("/status", status.Get).
 		Methods(http.MethodGet)
+	m.HandleFunc("/status/translation", status.Translation(s.quota)).
+		Methods(http.MethodGet)
+	m.Handle("/metrics", s.metrics).
+		Methods(http.MethodGet)
+	m.HandleFunc("/translations", translations.List(s.methods)).
+		Methods(http.MethodGet)
+	m.HandleFunc("/problems", problems.List).
+		Methods(http.MethodGet)
+	m.HandleFunc("/problems/{code}", problems.Get).
+		Methods(http.MethodGet)
 
 	return m
 }
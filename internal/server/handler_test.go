@@ -2,23 +2,277 @@ package server
 
 import (
 	"context"
+	_errors "errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
+	"github.com/jacklaaa89/pokeapi/internal/api/format/xml"
+	"github.com/jacklaaa89/pokeapi/internal/api/metrics"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
+	"github.com/jacklaaa89/pokeapi/internal/pokeapi"
+	"github.com/jacklaaa89/pokeapi/internal/server/helpers"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
 )
 
-func TestHandler(t *testing.T) {
-	h := Handler()
+// species a pokemon.SpeciesSource implementation which returns a fixed species.
+type species struct {
+	s *pokeapi.Species
+}
+
+// Species implements pokemon.SpeciesSource interface.
+func (f *species) Species(context.Context, string) (*pokeapi.Species, error) { return f.s, nil }
+
+// Pokemon implements pokemon.SpeciesSource interface.
+func (f *species) Pokemon(context.Context, string) (*pokeapi.Pokemon, error) {
+	return nil, _errors.New("not implemented")
+}
+
+// newSpecies initialises a species source which returns a species with the name and description.
+func newSpecies(name, description string) *species {
+	return &species{s: &pokeapi.Species{
+		Name:    name,
+		Habitat: &pokeapi.NamedAPIResource{Name: "cave"},
+		FlavorText: []*pokeapi.FlavorText{
+			{Text: description, Language: &pokeapi.NamedAPIResource{Name: "en"}},
+		},
+	}}
+}
+
+// prefix a translator which prefixes the input.
+func prefix(p string) translation.Translator {
+	return translation.TranslatorFunc(func(_ context.Context, input string, _ translation.Method) (string, error) {
+		return p + input, nil
+	})
+}
+
+// get performs a GET request against the handler.
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	return getAccept(t, h, path, "")
+}
+
+// getAccept performs a GET request against the handler which accepts the content type.
+func getAccept(t *testing.T, h http.Handler, path, accept string) *httptest.ResponseRecorder {
 	res := httptest.NewRecorder()
-	req, err := http.NewRequestWithContext(
-		context.Background(), http.MethodGet,
-		"/status", nil,
-	)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
 	require.NoError(t, err)
+	req.Header.Set("Accept", accept)
 
 	h.ServeHTTP(res, req)
+	return res
+}
+
+func TestHandler(t *testing.T) {
+	res := get(t, New(newSpecies("mewtwo", "text"), prefix("")).Handler(), "/status")
 	assert.Equal(t, http.StatusOK, res.Code)
 }
+
+func TestHandler_Metrics(t *testing.T) {
+	res := get(t, New(newSpecies("mewtwo", "text"), prefix("")).Handler(), "/metrics")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Header().Get("Content-Type"), "text/plain")
+}
+
+func TestWithMetrics(t *testing.T) {
+	r := metrics.NewPrometheus()
+	h := New(newSpecies("mewtwo", "text"), prefix(""), WithMetrics(r)).Handler()
+	get(t, h, "/status")
+
+	// the requests are recorded in, and served from, the supplied registry.
+	b := new(strings.Builder)
+	_, err := r.WriteTo(b)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `route="/status"`)
+	assert.Contains(t, get(t, h, "/metrics").Body.String(), `route="/status"`)
+
+	// a server initialised without a registry does not share one.
+	other := New(newSpecies("mewtwo", "text"), prefix("")).Handler()
+	assert.NotContains(t, get(t, other, "/metrics").Body.String(), `route="/status"`)
+}
+
+func TestServer_Handler(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Server   *Server
+		Path     string
+		Accept   string
+		Expected func(t *testing.T, res *httptest.ResponseRecorder)
+	}{
+		{
+			Name:   "Species",
+			Server: New(newSpecies("mewtwo", "It was created."), prefix("")),
+			Path:   "/pokemon/mewtwo",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Contains(t, res.Body.String(), `"name":"mewtwo"`)
+				assert.Contains(t, res.Body.String(), `"description":"It was created."`)
+			},
+		},
+		{
+			Name:   "Translated",
+			Server: New(newSpecies("zubat", "It lives in caves."), prefix("yoda: ")),
+			Path:   "/pokemon/zubat/translated",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Contains(t, res.Body.String(), `"description":"yoda: It lives in caves."`)
+			},
+		},
+		{
+			Name: "Quota",
+			Server: New(newSpecies("mewtwo", "text"), prefix(""), WithQuota(func() translation.Quota {
+				return translation.Quota{Limit: 5, Remaining: 2}
+			})),
+			Path: "/status/translation",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Contains(t, res.Body.String(), `"limit":5`)
+				assert.Contains(t, res.Body.String(), `"remaining":2`)
+			},
+		},
+		{
+			Name:   "Encoder",
+			Server: New(newSpecies("mewtwo", "text"), prefix(""), WithEncoder("application/xml", xml.New())),
+			Path:   "/pokemon/mewtwo",
+			Accept: "application/xml",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.True(t, strings.HasPrefix(res.Body.String(), "<"))
+				assert.Equal(t, "application/xml", res.Header().Get("Content-Type"))
+			},
+		},
+		{
+			Name:   "EncoderNotAccepted",
+			Server: New(newSpecies("mewtwo", "text"), prefix(""), WithEncoder("application/xml", xml.New())),
+			Path:   "/pokemon/mewtwo",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+			},
+		},
+		{
+			Name:   "Problem",
+			Server: New(newSpecies("mewtwo", "text"), prefix("")),
+			Path:   "/problems/unknown",
+			Accept: helpers.ProblemXML,
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, res.Code)
+				assert.Equal(t, helpers.ProblemXML, res.Header().Get("Content-Type"))
+				assert.Contains(t, res.Body.String(), `<problem xmlns="urn:ietf:rfc:7807">`)
+			},
+		},
+		{
+			Name:   "ProblemEncoder",
+			Server: New(newSpecies("mewtwo", "text"), prefix(""), WithEncoder("application/problem+yaml", json.New())),
+			Path:   "/problems/unknown",
+			Accept: "application/problem+yaml",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, res.Code)
+				assert.Equal(t, "application/problem+yaml", res.Header().Get("Content-Type"))
+				assert.Contains(t, res.Body.String(), `"type":"/problems/not_found"`)
+			},
+		},
+		{
+			Name:   "Methods",
+			Server: New(newSpecies("mewtwo", "text"), prefix(""), WithMethods(translation.NewRegistry(translation.Yoda))),
+			Path:   "/translations",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Contains(t, res.Body.String(), `"data":[{"name":"yoda"}]`)
+			},
+		},
+		{
+			Name:   "MethodNotAvailable",
+			Server: New(newSpecies("mewtwo", "text"), prefix(""), WithMethods(translation.NewRegistry(translation.Yoda))),
+			Path:   "/pokemon/mewtwo/translated?method=shakespeare",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, res.Code)
+			},
+		},
+		{
+			Name: "Middleware",
+			Server: New(newSpecies("mewtwo", "text"), prefix(""), WithMiddleware(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("X-Test", "true")
+					next.ServeHTTP(w, r)
+				})
+			})),
+			Path: "/status",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Equal(t, "true", res.Header().Get("X-Test"))
+			},
+		},
+		{
+			Name:   "RequestID",
+			Server: New(newSpecies("mewtwo", "text"), prefix("")),
+			Path:   "/pokemon/mewtwo",
+			Expected: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				id := res.Header().Get(errors.RequestIDHeader)
+				require.NotEmpty(t, id)
+				assert.Contains(t, res.Body.String(), `"request_id":"`+id+`"`)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Expected(t, getAccept(t, tc.Server.Handler(), tc.Path, tc.Accept))
+		})
+	}
+}
+
+// TestServer_Instances ensures that servers initialised with different dependencies
+// do not share them when run in the same process.
+func TestServer_Instances(t *testing.T) {
+	a := New(newSpecies("zubat", "It lives in caves."), prefix("a: ")).Handler()
+	b := New(newSpecies("zubat", "It lives in caves."), prefix("b: ")).Handler()
+
+	assert.Contains(t, get(t, a, "/pokemon/zubat/translated").Body.String(), `"description":"a: It lives in caves."`)
+	assert.Contains(t, get(t, b, "/pokemon/zubat/translated").Body.String(), `"description":"b: It lives in caves."`)
+}
+
+func TestWithTrust(t *testing.T) {
+	do := func(s *Server) string {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.Header.Set(errors.RequestIDHeader, "gateway-id")
+		s.Handler().ServeHTTP(res, req)
+		return res.Header().Get(errors.RequestIDHeader)
+	}
+
+	// the request id sent with the request is only used when it is trusted.
+	assert.NotEqual(t, "gateway-id", do(New(newSpecies("mewtwo", "text"), prefix(""))))
+	assert.Equal(t, "gateway-id", do(New(newSpecies("mewtwo", "text"), prefix(""), WithTrust(middleware.TrustAll()))))
+}
+
+func TestWithClock(t *testing.T) {
+	fixed := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s := New(newSpecies("mewtwo", "text"), prefix(""), WithClock(func() time.Time { return fixed }))
+	assert.Equal(t, fixed, s.clock())
+
+	// a nil clock keeps the default.
+	s = New(newSpecies("mewtwo", "text"), prefix(""), WithClock(nil))
+	assert.NotNil(t, s.clock)
+}
+
+func TestWithTracer(t *testing.T) {
+	e := trace.NewMemory()
+	h := New(newSpecies("mewtwo", "text"), prefix(""), WithTracer(trace.New(e))).Handler()
+
+	res := get(t, h, "/pokemon/mewtwo")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.NotEmpty(t, res.Header().Get(trace.TraceparentHeader))
+
+	spans := e.Spans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /pokemon/{name}", spans[0].Name)
+}
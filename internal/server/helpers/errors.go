@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"context"
+	_errors "errors"
 	"net/http"
 
 	"github.com/jacklaaa89/pokeapi/internal/api/errors"
@@ -31,28 +32,64 @@ func (*invalidRequestError) StatusCode() int   { return http.StatusBadRequest }
 // and errors.CodeInvalidRequest as the error code.
 func InvalidRequest(err error) error { return &invalidRequestError{err} }
 
+type notFoundError struct{ err error }
+
+func (n *notFoundError) Error() string   { return n.err.Error() }
+func (*notFoundError) Code() errors.Code { return errors.CodeNotFound }
+func (*notFoundError) StatusCode() int   { return http.StatusNotFound }
+
+// NotFound wraps an error to return http.StatusNotFound as the status code
+// and errors.CodeNotFound as the error code.
+func NotFound(err error) error { return &notFoundError{err} }
+
 // RespondError this allows us to write an error to the supplied http.ResponseWriter
+//
+// errors returned from an upstream API are mapped using MapUpstream, so the status code
+// of the upstream API is never returned directly.
+//
+// the message is returned in the language preferred by the Accept-Language header, see
+// Languages for the supported languages, the error code is the same in every language.
+//
+// when the client prefers a problem format there is an encoder for, i.e application/problem+json
+// or application/problem+xml, the error is written as a RFC 7807 problem, see ProblemTypes
+// for the catalogue of problem types.
 func RespondError(ctx context.Context, w http.ResponseWriter, err error) {
 	if err == nil {
 		return
 	}
 
 	var (
+		n      = middleware.NegotiationFrom(ctx)
+		lang   = MatchLanguage(n.AcceptLanguage)
 		status = http.StatusInternalServerError
 		msg    = err.Error()
 		code   = errors.CodeServerError
 	)
 
-	switch v := err.(type) {
-	case *errors.Error:
-		if v.StatusCode > 0 {
-			status = v.StatusCode
-		}
-		code = v.Code
-		msg = string(v.Code)
-	case compoundError:
-		status = v.StatusCode()
-		code = v.Code()
+	var (
+		apiErr *errors.Error
+		ce     compoundError
+	)
+
+	switch {
+	case _errors.As(err, &apiErr):
+		m := MapUpstream(apiErr)
+		status, code, msg = m.Status, m.Code, m.Detail
+	case _errors.As(err, &ce):
+		status = ce.StatusCode()
+		code = ce.Code()
+	}
+
+	// the messages of our own errors are in english, for any other language the
+	// message from the catalogue for the error code is used instead.
+	if lang != defaultLanguage {
+		msg = Message(lang, code)
+	}
+	w.Header().Set("Content-Language", lang.String())
+
+	if f := problemFormat(n.Accept, encoders(ctx)); f != "" {
+		writeProblem(ctx, w, f, newProblem(ctx, status, code, msg))
+		return
 	}
 
 	res := &errorResponse{Error: msg, Code: code}
@@ -38,6 +38,14 @@ func TestInvalidRequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, irErr.(compoundError).StatusCode())
 }
 
+func TestNotFound(t *testing.T) {
+	nfErr := NotFound(_fmt.Errorf("a test error"))
+	assert.Implements(t, (*compoundError)(nil), nfErr)
+	assert.Equal(t, errors.CodeNotFound, nfErr.(compoundError).Code())
+	assert.Equal(t, http.StatusNotFound, nfErr.(compoundError).StatusCode())
+	assert.Equal(t, "a test error", nfErr.Error())
+}
+
 func TestRespondError(t *testing.T) {
 	tt := []struct {
 		Name     string
@@ -90,9 +98,9 @@ func TestRespondError(t *testing.T) {
 				Source:     "could not decode response",
 			},
 			Expected: func(t *testing.T, code int, err *errorResponse) {
-				assert.Equal(t, http.StatusInternalServerError, code)
-				assert.Equal(t, errors.CodeEncodingError, err.Code)
-				assert.Equal(t, string(errors.CodeEncodingError), err.Error)
+				assert.Equal(t, http.StatusBadGateway, code)
+				assert.Equal(t, CodeUpstreamError, err.Code)
+				assert.Equal(t, "the upstream API failed to handle the request", err.Error)
 			},
 		},
 	}
@@ -106,9 +114,6 @@ func TestRespondError(t *testing.T) {
 				enc = json.New()
 			}
 
-			// get the server to respond using the default encoder.
-			WithEncoder(enc)
-
 			// produce a handler func to wrap the test execution in.
 			// this is required as the RespondError handler requires the
 			// request id is placed in the context from middleware.WithRequestID
@@ -119,7 +124,8 @@ func TestRespondError(t *testing.T) {
 			req, err := http.NewRequest(http.MethodGet, "/get", nil)
 			require.NoError(st, err)
 
-			h = withMiddleware(h, middleware.WithRequestID(), middleware.WithLogger(fmt.New(fmt.LevelNone)))
+			// get the server to respond using the encoder.
+			h = withMiddleware(h, middleware.WithRequestID(), middleware.WithLogger(fmt.New(fmt.LevelNone)), middleware.WithEncoders(map[string]format.Encoder{DefaultContentType: enc}))
 			h.ServeHTTP(r, req)
 
 			var res = new(response)
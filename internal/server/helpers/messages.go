@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+)
+
+// defaultLanguage the language used when the client accepts none of the supported languages.
+var defaultLanguage = language.English
+
+// messages the error messages returned from the server keyed by language and error code.
+// every language must define a message for every error code.
+var messages = map[language.Tag]map[errors.Code]string{
+	language.English: {
+		errors.CodeUnknownError:      "An unknown error occurred.",
+		errors.CodeInvalidRequest:    "The request was malformed or contained invalid parameters.",
+		errors.CodeNotFound:          "The requested resource could not be found.",
+		errors.CodeServerError:       "An unexpected error occurred handling the request.",
+		errors.CodeServerUnavailable: "The service is temporarily unavailable, please retry later.",
+		CodeUpstreamError:            "An upstream service failed to handle the request.",
+		CodeUpstreamTimeout:          "An upstream service did not respond in time.",
+		CodeUpstreamAuthentication:   "An upstream service rejected the request.",
+	},
+	language.German: {
+		errors.CodeUnknownError:      "Ein unbekannter Fehler ist aufgetreten.",
+		errors.CodeInvalidRequest:    "Die Anfrage war fehlerhaft oder enthielt ungültige Parameter.",
+		errors.CodeNotFound:          "Die angeforderte Ressource wurde nicht gefunden.",
+		errors.CodeServerError:       "Bei der Bearbeitung der Anfrage ist ein unerwarteter Fehler aufgetreten.",
+		errors.CodeServerUnavailable: "Der Dienst ist vorübergehend nicht verfügbar, bitte versuchen Sie es später erneut.",
+		CodeUpstreamError:            "Ein vorgelagerter Dienst konnte die Anfrage nicht bearbeiten.",
+		CodeUpstreamTimeout:          "Ein vorgelagerter Dienst hat nicht rechtzeitig geantwortet.",
+		CodeUpstreamAuthentication:   "Ein vorgelagerter Dienst hat die Anfrage abgelehnt.",
+	},
+	language.French: {
+		errors.CodeUnknownError:      "Une erreur inconnue est survenue.",
+		errors.CodeInvalidRequest:    "La requête est mal formée ou contient des paramètres invalides.",
+		errors.CodeNotFound:          "La ressource demandée est introuvable.",
+		errors.CodeServerError:       "Une erreur inattendue est survenue lors du traitement de la requête.",
+		errors.CodeServerUnavailable: "Le service est temporairement indisponible, veuillez réessayer plus tard.",
+		CodeUpstreamError:            "Un service en amont n'a pas pu traiter la requête.",
+		CodeUpstreamTimeout:          "Un service en amont n'a pas répondu à temps.",
+		CodeUpstreamAuthentication:   "Un service en amont a refusé la requête.",
+	},
+	language.Spanish: {
+		errors.CodeUnknownError:      "Se produjo un error desconocido.",
+		errors.CodeInvalidRequest:    "La solicitud tenía un formato incorrecto o parámetros no válidos.",
+		errors.CodeNotFound:          "No se encontró el recurso solicitado.",
+		errors.CodeServerError:       "Se produjo un error inesperado al procesar la solicitud.",
+		errors.CodeServerUnavailable: "El servicio no está disponible temporalmente, vuelva a intentarlo más tarde.",
+		CodeUpstreamError:            "Un servicio externo no pudo procesar la solicitud.",
+		CodeUpstreamTimeout:          "Un servicio externo no respondió a tiempo.",
+		CodeUpstreamAuthentication:   "Un servicio externo rechazó la solicitud.",
+	},
+}
+
+// catalogue the message catalogue built from messages.
+var catalogue = catalog.NewBuilder(catalog.Fallback(defaultLanguage))
+
+// init builds the message catalogue.
+func init() {
+	for tag, msgs := range messages {
+		for code, msg := range msgs {
+			if err := catalogue.SetString(tag, string(code), msg); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// Languages retrieves the languages error messages are available in, the default language is first.
+func Languages() []language.Tag { return catalogue.Languages() }
+
+// MatchLanguage determines the supported language preferred by the supplied Accept-Language header
+// the default language is returned if the header is empty, invalid or accepts no supported language.
+func MatchLanguage(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return defaultLanguage
+	}
+
+	_, i, c := catalogue.Matcher().Match(tags...)
+	if c == language.No {
+		return defaultLanguage
+	}
+	return Languages()[i]
+}
+
+// Message retrieves the error message for the code in the supplied language, the message
+// for errors.CodeUnknownError is used for a code which is not in the catalogue.
+func Message(tag language.Tag, code errors.Code) string {
+	if _, ok := messages[defaultLanguage][code]; !ok {
+		code = errors.CodeUnknownError
+	}
+	return message.NewPrinter(tag, message.Catalog(catalogue)).Sprintf(string(code))
+}
@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+)
+
+func TestMessages(t *testing.T) {
+	// every language must define a message for every code in the problem catalogue.
+	for tag, msgs := range messages {
+		for _, pt := range ProblemTypes() {
+			assert.NotEmpty(t, msgs[pt.Code], "%s: %s", tag, pt.Code)
+		}
+		assert.Len(t, msgs, len(ProblemTypes()), tag.String())
+	}
+
+	assert.Equal(t, language.English, Languages()[0])
+}
+
+func TestMatchLanguage(t *testing.T) {
+	tt := []struct {
+		Name           string
+		AcceptLanguage string
+		Expected       language.Tag
+	}{
+		{Name: "Empty", AcceptLanguage: "", Expected: language.English},
+		{Name: "Invalid", AcceptLanguage: "!!", Expected: language.English},
+		{Name: "Unsupported", AcceptLanguage: "ja", Expected: language.English},
+		{Name: "Exact", AcceptLanguage: "de", Expected: language.German},
+		{Name: "Region", AcceptLanguage: "es-MX", Expected: language.Spanish},
+		{Name: "Quality", AcceptLanguage: "ja, de;q=0.5, fr;q=0.8", Expected: language.French},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			assert.Equal(st, tc.Expected, MatchLanguage(tc.AcceptLanguage))
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "The requested resource could not be found.", Message(language.English, errors.CodeNotFound))
+	assert.Equal(t, "No se encontró el recurso solicitado.", Message(language.Spanish, errors.CodeNotFound))
+	assert.Equal(t, "Une erreur inconnue est survenue.", Message(language.French, "unknown"))
+}
@@ -0,0 +1,142 @@
+package helpers
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/format"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+)
+
+const (
+	// ProblemJSON the media type of a RFC 7807 problem encoded as JSON.
+	ProblemJSON = "application/problem+json"
+	// ProblemXML the media type of a RFC 7807 problem encoded as XML.
+	ProblemXML = "application/problem+xml"
+	// ProblemTypeBase the base URI of each problem type, the code is appended to it
+	// the URI resolves to the documentation of the problem type served by the server.
+	ProblemTypeBase = "/problems/"
+)
+
+// Problem a RFC 7807 problem details object, extended with the error code and request id.
+type Problem struct {
+	XMLName   xml.Name    `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type      string      `json:"type" xml:"type"`                                 // Type the URI which identifies the problem type.
+	Title     string      `json:"title" xml:"title"`                               // Title the summary of the problem type.
+	Status    int         `json:"status" xml:"status"`                             // Status the HTTP status code.
+	Detail    string      `json:"detail,omitempty" xml:"detail,omitempty"`         // Detail the explanation of this occurrence.
+	Instance  string      `json:"instance,omitempty" xml:"instance,omitempty"`     // Instance the URI of this occurrence.
+	Code      errors.Code `json:"code" xml:"code"`                                 // Code the error code.
+	RequestID string      `json:"request_id,omitempty" xml:"request_id,omitempty"` // RequestID the id of the request.
+}
+
+// ProblemType documents a type of problem returned from the server.
+type ProblemType struct {
+	Code        errors.Code `json:"code"`        // Code the error code the problem type represents.
+	Type        string      `json:"type"`        // Type the URI which identifies the problem type.
+	Title       string      `json:"title"`       // Title the summary of the problem type.
+	Status      int         `json:"status"`      // Status the HTTP status code usually returned.
+	Description string      `json:"description"` // Description describes when the problem occurs.
+}
+
+// problemTypes the catalogue of problem types, one for each errors.Code returned from the server.
+// the codes of the errors returned from an upstream API are not listed, they are mapped using
+// MapUpstream before they are returned.
+// the type and title of a problem type must not change once published.
+var problemTypes = map[errors.Code]ProblemType{}
+
+// init builds the catalogue of problem types.
+func init() {
+	for _, pt := range []ProblemType{
+		{errors.CodeUnknownError, "", "Unknown Error", http.StatusInternalServerError,
+			"An error occurred which could not be classified."},
+		{errors.CodeInvalidRequest, "", "Invalid Request", http.StatusBadRequest,
+			"The request was malformed or contained invalid parameters."},
+		{errors.CodeNotFound, "", "Not Found", http.StatusNotFound,
+			"The requested resource could not be found."},
+		{errors.CodeServerError, "", "Server Error", http.StatusInternalServerError,
+			"An unexpected error occurred handling the request."},
+		{errors.CodeServerUnavailable, "", "Server Unavailable", http.StatusServiceUnavailable,
+			"The upstream API is temporarily unavailable, retry later."},
+		{CodeUpstreamError, "", "Upstream Error", http.StatusBadGateway,
+			"An upstream API failed to handle the request or returned a response which could not be used."},
+		{CodeUpstreamTimeout, "", "Upstream Timeout", http.StatusGatewayTimeout,
+			"An upstream API did not respond in time."},
+		{CodeUpstreamAuthentication, "", "Upstream Authentication Error", http.StatusBadGateway,
+			"An upstream API rejected the credentials of the server, this is not caused by the request."},
+	} {
+		pt.Type = ProblemTypeBase + string(pt.Code)
+		problemTypes[pt.Code] = pt
+	}
+}
+
+// ProblemTypes retrieves the catalogue of problem types sorted by code.
+func ProblemTypes() []ProblemType {
+	out := make([]ProblemType, 0, len(problemTypes))
+	for _, pt := range problemTypes {
+		out = append(out, pt)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+// LookupProblemType retrieves the problem type for the supplied code.
+func LookupProblemType(code errors.Code) (ProblemType, bool) {
+	pt, ok := problemTypes[code]
+	return pt, ok
+}
+
+// problemPrefix the prefix of the media type of a RFC 7807 problem.
+const problemPrefix = "application/problem+"
+
+// isProblem determines whether the media type is the media type of a RFC 7807 problem.
+func isProblem(mediaType string) bool { return strings.HasPrefix(mediaType, problemPrefix) }
+
+// problemFormat determines which problem format, if any, is preferred by the Accept header
+// from the problem formats there is an encoder for, an empty string is returned if the client
+// prefers another format.
+func problemFormat(accept string, e map[string]format.Encoder) string {
+	types := accepted(accept)
+	if len(types) == 0 || !isProblem(types[0]) {
+		return ""
+	}
+	if _, ok := e[types[0]]; !ok {
+		return ""
+	}
+	return types[0]
+}
+
+// newProblem generates the problem details for an error.
+func newProblem(ctx context.Context, status int, code errors.Code, detail string) *Problem {
+	pt, ok := LookupProblemType(code)
+	if !ok {
+		pt = problemTypes[errors.CodeUnknownError]
+	}
+
+	return &Problem{
+		Type:      pt.Type,
+		Title:     pt.Title,
+		Status:    status,
+		Detail:    detail,
+		Instance:  middleware.NegotiationFrom(ctx).Instance,
+		Code:      code,
+		RequestID: middleware.RequestID(ctx),
+	}
+}
+
+// writeProblem writes the problem in the requested format using the encoder of the format.
+func writeProblem(ctx context.Context, w http.ResponseWriter, mediaType string, p *Problem) {
+	l := middleware.Logger(ctx)
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(p.Status)
+
+	if err := encoders(ctx)[mediaType].EncodeTo(w, p); err != nil {
+		l.Errorf("could not encode problem into response: %v", err)
+	}
+}
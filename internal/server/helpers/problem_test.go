@@ -0,0 +1,194 @@
+package helpers
+
+import (
+	"encoding/xml"
+	_fmt "fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/format"
+	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
+	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+)
+
+func TestProblemFormat(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Accept   string
+		Encoders map[string]format.Encoder
+		Expected string
+	}{
+		{Name: "None", Accept: "", Expected: ""},
+		{Name: "JSON", Accept: "application/json", Expected: ""},
+		{Name: "ProblemJSON", Accept: "application/problem+json", Expected: ProblemJSON},
+		{Name: "ProblemXML", Accept: "application/problem+xml, application/xml", Expected: ProblemXML},
+		{Name: "Quality", Accept: "application/problem+json;q=0.5, application/problem+xml", Expected: ProblemXML},
+		{Name: "PreferredJSON", Accept: "application/json, application/problem+json;q=0.9", Expected: ""},
+		{Name: "Rejected", Accept: "application/problem+json;q=0", Expected: ""},
+		{Name: "Invalid", Accept: "not a media type", Expected: ""},
+		{
+			Name:     "NoEncoder",
+			Accept:   "application/problem+xml",
+			Encoders: map[string]format.Encoder{ProblemJSON: json.New()},
+			Expected: "",
+		},
+		{
+			Name:     "Encoder",
+			Accept:   "application/problem+yaml",
+			Encoders: map[string]format.Encoder{"application/problem+yaml": json.New()},
+			Expected: "application/problem+yaml",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			e := tc.Encoders
+			if e == nil {
+				e = DefaultEncoders()
+			}
+			assert.Equal(t, tc.Expected, problemFormat(tc.Accept, e))
+		})
+	}
+}
+
+func TestProblemTypes(t *testing.T) {
+	types := ProblemTypes()
+	require.NotEmpty(t, types)
+	for _, pt := range types {
+		assert.Equal(t, ProblemTypeBase+string(pt.Code), pt.Type)
+		assert.NotEmpty(t, pt.Title)
+		assert.NotZero(t, pt.Status)
+		assert.NotEmpty(t, pt.Description)
+	}
+
+	_, ok := LookupProblemType("unknown")
+	assert.False(t, ok)
+
+	// the codes of upstream errors are only ever returned once mapped using MapUpstream.
+	for _, code := range []errors.Code{
+		errors.CodeUnauthorized, errors.CodeForbidden, errors.CodeRateLimitExceeded,
+		errors.CodeEncodingError, errors.CodeRequestError, errors.CodeHTTPClientError,
+	} {
+		_, ok = LookupProblemType(code)
+		assert.False(t, ok, code)
+	}
+	pt, ok := LookupProblemType(CodeUpstreamAuthentication)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadGateway, pt.Status)
+}
+
+func TestRespondError_Problem(t *testing.T) {
+	apiErr := &errors.Error{
+		Code:       errors.CodeNotFound,
+		StatusCode: http.StatusNotFound,
+		Method:     http.MethodGet,
+		Resource:   "/pokemon-species/unknown",
+		Source:     "upstream details",
+	}
+
+	tt := []struct {
+		Name           string
+		Accept         string
+		AcceptLanguage string
+		Error          error
+		Expected       func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			Name:   "JSON",
+			Accept: ProblemJSON,
+			Error:  apiErr,
+			Expected: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+				assert.Equal(t, ProblemJSON, r.Header().Get("Content-Type"))
+
+				p := new(Problem)
+				require.NoError(t, json.New().Decode(r.Body, p))
+				assert.Equal(t, "/problems/not_found", p.Type)
+				assert.Equal(t, "Not Found", p.Title)
+				assert.Equal(t, http.StatusNotFound, p.Status)
+				assert.Equal(t, "the requested resource could not be found", p.Detail)
+				assert.Equal(t, "/pokemon/unknown?api_key=%5BREDACTED%5D", p.Instance)
+				assert.Equal(t, errors.CodeNotFound, p.Code)
+				assert.NotEmpty(t, p.RequestID)
+			},
+		},
+		{
+			Name:   "XML",
+			Accept: ProblemXML,
+			Error:  InvalidRequest(_fmt.Errorf("invalid method")),
+			Expected: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+				assert.Equal(t, ProblemXML, r.Header().Get("Content-Type"))
+				assert.Contains(t, r.Body.String(), `<problem xmlns="urn:ietf:rfc:7807">`)
+
+				p := new(Problem)
+				require.NoError(t, xml.NewDecoder(r.Body).Decode(p))
+				assert.Equal(t, "/problems/invalid_request", p.Type)
+				assert.Equal(t, "invalid method", p.Detail)
+				assert.Equal(t, errors.CodeInvalidRequest, p.Code)
+			},
+		},
+		{
+			Name:           "Localized",
+			Accept:         ProblemJSON,
+			AcceptLanguage: "fr-CH, de;q=0.9",
+			Error:          InvalidRequest(_fmt.Errorf("invalid method")),
+			Expected: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, "fr", r.Header().Get("Content-Language"))
+
+				p := new(Problem)
+				require.NoError(t, json.New().Decode(r.Body, p))
+				assert.Equal(t, "La requête est mal formée ou contient des paramètres invalides.", p.Detail)
+				assert.Equal(t, errors.CodeInvalidRequest, p.Code)
+			},
+		},
+		{
+			Name:           "LocalizedLegacy",
+			Accept:         "application/json",
+			AcceptLanguage: "de",
+			Error:          apiErr,
+			Expected: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, "de", r.Header().Get("Content-Language"))
+
+				res := new(response)
+				require.NoError(t, json.New().Decode(r.Body, res))
+				assert.Equal(t, "Die angeforderte Ressource wurde nicht gefunden.", res.Error.Error)
+				assert.Equal(t, errors.CodeNotFound, res.Error.Code)
+			},
+		},
+		{
+			Name:   "Legacy",
+			Accept: "application/json",
+			Error:  apiErr,
+			Expected: func(t *testing.T, r *httptest.ResponseRecorder) {
+				res := new(response)
+				require.NoError(t, json.New().Decode(r.Body, res))
+				assert.Equal(t, errors.CodeNotFound, res.Error.Code)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				RespondError(req.Context(), w, tc.Error)
+			})
+
+			h = withMiddleware(h,
+				middleware.WithNegotiation(), middleware.WithRequestID(), middleware.WithLogger(fmt.New(fmt.LevelNone)))
+
+			r := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/pokemon/unknown?api_key=secret", nil)
+			req.Header.Set("Accept", tc.Accept)
+			req.Header.Set("Accept-Language", tc.AcceptLanguage)
+			h.ServeHTTP(r, req)
+			tc.Expected(st, r)
+		})
+	}
+}
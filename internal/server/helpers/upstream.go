@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"net/http"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+)
+
+// the error codes returned from the server when a call to an upstream API fails
+// which have no equivalent in the upstream APIs.
+const (
+	// CodeUpstreamError the upstream API failed or returned a response we could not use.
+	CodeUpstreamError errors.Code = "upstream_error"
+	// CodeUpstreamTimeout the upstream API did not respond in time.
+	CodeUpstreamTimeout errors.Code = "upstream_timeout"
+	// CodeUpstreamAuthentication the upstream API rejected the credentials of the server.
+	CodeUpstreamAuthentication errors.Code = "upstream_authentication_error"
+)
+
+// UpstreamMapping describes the error returned from the server when a call to an
+// upstream API fails.
+type UpstreamMapping struct {
+	Status int         // Status the HTTP status code returned from the server.
+	Code   errors.Code // Code the error code returned from the server.
+	Detail string      // Detail the human-readable explanation returned from the server.
+}
+
+// upstreamRule maps the errors matched by Match to Mapping.
+type upstreamRule struct {
+	Match   func(err error) bool
+	Mapping UpstreamMapping
+}
+
+// upstreamRules the rules used to map upstream errors, the first matching rule is used.
+//
+// the upstream status code is never passed through, a 500 from the upstream API is a
+// 502 from the server and a 401 due to a bad API key is a failure of the server, not
+// of the client.
+var upstreamRules = []upstreamRule{
+	{
+		Match:   errors.IsNotFound,
+		Mapping: UpstreamMapping{http.StatusNotFound, errors.CodeNotFound, "the requested resource could not be found"},
+	},
+	{
+		Match:   errors.IsTimeout,
+		Mapping: UpstreamMapping{http.StatusGatewayTimeout, CodeUpstreamTimeout, "the upstream API did not respond in time"},
+	},
+	{
+		Match:   errors.IsUnauthorized,
+		Mapping: UpstreamMapping{http.StatusBadGateway, CodeUpstreamAuthentication, "the upstream API rejected the request"},
+	},
+	{
+		Match:   errors.IsRateLimited,
+		Mapping: UpstreamMapping{http.StatusServiceUnavailable, errors.CodeServerUnavailable, "the upstream API is temporarily unavailable, retry later"},
+	},
+	{
+		Match:   func(err error) bool { return errors.Is(err, errors.CodeRequestError) },
+		Mapping: UpstreamMapping{http.StatusInternalServerError, errors.CodeServerError, "the request to the upstream API could not be created"},
+	},
+}
+
+// defaultUpstreamMapping the mapping used when no rule matches, i.e a 5xx response,
+// a failed connection or a response which could not be decoded.
+var defaultUpstreamMapping = UpstreamMapping{
+	Status: http.StatusBadGateway,
+	Code:   CodeUpstreamError,
+	Detail: "the upstream API failed to handle the request",
+}
+
+// MapUpstream maps an error returned from an upstream API to the error returned from
+// the server, so that the semantics of the upstream API do not leak to our clients.
+func MapUpstream(e *errors.Error) UpstreamMapping {
+	for _, r := range upstreamRules {
+		if r.Match(e) {
+			return r.Mapping
+		}
+	}
+	return defaultUpstreamMapping
+}
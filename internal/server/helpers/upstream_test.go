@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+)
+
+func TestMapUpstream(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Error    *errors.Error
+		Status   int
+		Expected errors.Code
+	}{
+		{
+			Name:     "NotFound",
+			Error:    &errors.Error{Code: errors.CodeNotFound, StatusCode: http.StatusNotFound},
+			Status:   http.StatusNotFound,
+			Expected: errors.CodeNotFound,
+		},
+		{
+			Name:     "ServerError",
+			Error:    &errors.Error{Code: errors.CodeServerError, StatusCode: http.StatusInternalServerError},
+			Status:   http.StatusBadGateway,
+			Expected: CodeUpstreamError,
+		},
+		{
+			Name:     "ServerUnavailable",
+			Error:    &errors.Error{Code: errors.CodeServerUnavailable, StatusCode: http.StatusServiceUnavailable},
+			Status:   http.StatusBadGateway,
+			Expected: CodeUpstreamError,
+		},
+		{
+			Name:     "GatewayTimeout",
+			Error:    &errors.Error{Code: errors.CodeServerError, StatusCode: http.StatusGatewayTimeout},
+			Status:   http.StatusGatewayTimeout,
+			Expected: CodeUpstreamTimeout,
+		},
+		{
+			Name:     "DeadlineExceeded",
+			Error:    &errors.Error{Code: errors.CodeHTTPClientError, Err: context.DeadlineExceeded},
+			Status:   http.StatusGatewayTimeout,
+			Expected: CodeUpstreamTimeout,
+		},
+		{
+			Name:     "Unauthorized",
+			Error:    &errors.Error{Code: errors.CodeUnauthorized, StatusCode: http.StatusUnauthorized},
+			Status:   http.StatusBadGateway,
+			Expected: CodeUpstreamAuthentication,
+		},
+		{
+			Name:     "Forbidden",
+			Error:    &errors.Error{Code: errors.CodeForbidden, StatusCode: http.StatusForbidden},
+			Status:   http.StatusBadGateway,
+			Expected: CodeUpstreamAuthentication,
+		},
+		{
+			Name:     "RateLimited",
+			Error:    &errors.Error{Code: errors.CodeRateLimitExceeded, StatusCode: http.StatusTooManyRequests},
+			Status:   http.StatusServiceUnavailable,
+			Expected: errors.CodeServerUnavailable,
+		},
+		{
+			Name:     "RequestError",
+			Error:    &errors.Error{Code: errors.CodeRequestError},
+			Status:   http.StatusInternalServerError,
+			Expected: errors.CodeServerError,
+		},
+		{
+			Name:     "ConnectionFailed",
+			Error:    &errors.Error{Code: errors.CodeHTTPClientError},
+			Status:   http.StatusBadGateway,
+			Expected: CodeUpstreamError,
+		},
+		{
+			Name:     "BadRequest",
+			Error:    &errors.Error{Code: errors.CodeInvalidRequest, StatusCode: http.StatusBadRequest},
+			Status:   http.StatusBadGateway,
+			Expected: CodeUpstreamError,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			m := MapUpstream(tc.Error)
+			assert.Equal(st, tc.Status, m.Status)
+			assert.Equal(st, tc.Expected, m.Code)
+			assert.NotEmpty(st, m.Detail)
+
+			_, ok := LookupProblemType(m.Code)
+			assert.True(st, ok)
+		})
+	}
+}
@@ -1,23 +1,33 @@
 package helpers
 
 import (
+	"bytes"
 	"context"
+	"mime"
 	"net/http"
-	"sync"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/jacklaaa89/pokeapi/internal/api/format"
 	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
+	"github.com/jacklaaa89/pokeapi/internal/api/format/xml"
 	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
 )
 
-// formatter the formatter to use when responding
-// allows us to change the format by just changing the formatter.
-// we default to responding with JSON.
-// the mutex is used to allow thread-safe changes of the formatter.
-var (
-	mu        sync.RWMutex
-	formatter = json.New()
-)
+// DefaultContentType the content type a response is encoded as when the client accepts none
+// of the content types there is an encoder for.
+const DefaultContentType = "application/json"
+
+// DefaultEncoders retrieves the encoders used when none are assigned to the request context
+// keyed by content type, responses are encoded as JSON and problems as JSON or XML.
+func DefaultEncoders() map[string]format.Encoder {
+	return map[string]format.Encoder{
+		DefaultContentType: json.New(),
+		ProblemJSON:        json.New(),
+		ProblemXML:         xml.New(),
+	}
+}
 
 // response the response from the server.
 type response struct {
@@ -26,33 +36,104 @@ type response struct {
 	Data      interface{}    `json:"data,omitempty"`  // Data is the response data.
 }
 
-// WithEncoder function which allows us to change the encoder to use
-// when responding to requests.
-func WithEncoder(f format.Encoder) {
-	if f == nil {
-		return
+// RespondOK responds by writing the supplied data to the supplied http.ResponseWriter with a http.StatusOK
+//
+// the ETag header is computed from the encoded data, rather than the whole response which
+// includes the request id, so that it only changes when the data changes. The data is
+// assigned as the render of the response, so a cached response is wrapped in the envelope
+// of each request it is served to.
+func RespondOK(ctx context.Context, w http.ResponseWriter, r interface{}) {
+	render := func(ctx context.Context, w http.ResponseWriter) {
+		if etag, err := entityTag(ctx, r); err == nil {
+			w.Header().Set("ETag", etag)
+		}
+		write(ctx, w, http.StatusOK, &response{RequestID: middleware.RequestID(ctx), Data: r})
+	}
+
+	middleware.SetRender(ctx, render)
+	render(ctx, w)
+}
+
+// encoders retrieves the encoders assigned to the context, i.e by the server, otherwise
+// the default encoders.
+func encoders(ctx context.Context) map[string]format.Encoder {
+	if e, ok := middleware.Encoders(ctx); ok {
+		return e
 	}
-	mu.Lock()
-	defer mu.Unlock()
-	formatter = f
+	return DefaultEncoders()
 }
 
-// RespondOK responds by writing the supplied data to the supplied http.ResponseWriter with a http.StatusOK
-func RespondOK(ctx context.Context, w http.ResponseWriter, r interface{}) {
-	write(ctx, w, http.StatusOK, &response{RequestID: middleware.RequestID(ctx), Data: r})
+// encoder negotiates the encoder used to encode a response from the content types accepted
+// by the client, the content type is returned with the encoder. The problem content types are
+// only used to encode errors, see problemFormat.
+func encoder(ctx context.Context) (string, format.Encoder) {
+	e := encoders(ctx)
+	for _, mt := range accepted(middleware.NegotiationFrom(ctx).Accept) {
+		if f, ok := e[mt]; ok && !isProblem(mt) {
+			return mt, f
+		}
+	}
+
+	if f, ok := e[DefaultContentType]; ok {
+		return DefaultContentType, f
+	}
+	return DefaultContentType, json.New()
+}
+
+// accepted retrieves the media types accepted by the Accept header in the order the client
+// prefers them, media types with a quality of zero are not accepted.
+func accepted(accept string) []string {
+	type mediaRange struct {
+		mediaType string
+		q         float64
+	}
+
+	ranges := make([]mediaRange, 0)
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+
+		if q > 0 {
+			ranges = append(ranges, mediaRange{mt, q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	out := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		out = append(out, r.mediaType)
+	}
+	return out
+}
+
+// entityTag computes the entity tag of the supplied data using the encoder.
+func entityTag(ctx context.Context, r interface{}) (string, error) {
+	_, f := encoder(ctx)
+	b := &bytes.Buffer{}
+	if err := f.EncodeTo(b, r); err != nil {
+		return "", err
+	}
+	return middleware.EntityTag(b.Bytes()), nil
 }
 
 // write helper function to write the supplied http response using the
 // encoder. This is thread-safe.
 func write(ctx context.Context, w http.ResponseWriter, code int, r interface{}) {
 	l := middleware.Logger(ctx)
+	ct, f := encoder(ctx)
 
-	mu.RLock()
-	defer mu.RUnlock()
-
-	w.Header().Set("Content-Type", formatter.ContentType())
+	w.Header().Set("Content-Type", ct)
 	w.WriteHeader(code)
-	if err := formatter.EncodeTo(w, r); err != nil {
+	if err := f.EncodeTo(w, r); err != nil {
 		l.Errorf("could not encode receiver into response: %v", err)
 	}
 }
@@ -45,43 +45,62 @@ func TestRespondOK(t *testing.T) {
 	res := &response{Data: ""}
 	json.NewDecoder(r.Body).Decode(res)
 	assert.Equal(t, "12345", res.Data)
+
+	// the entity tag is computed from the data, not the request id.
+	r2 := httptest.NewRecorder()
+	h.ServeHTTP(r2, req)
+	assert.NotEmpty(t, r.Header().Get("ETag"))
+	assert.Equal(t, r.Header().Get("ETag"), r2.Header().Get("ETag"))
 }
 
-func TestWithEncoder(t *testing.T) {
-	orig := formatter
+func TestEncoder(t *testing.T) {
+	withXML := DefaultEncoders()
+	withXML["application/xml"] = xml.New()
 
 	tt := []struct {
-		Name     string
-		Encoder  format.Encoder
-		Expected func(t *testing.T)
+		Name       string
+		Accept     string
+		Middleware []mux.MiddlewareFunc
+		Expected   string // Expected the negotiated content type.
 	}{
 		{
-			Name:    "NilEncoder",
-			Encoder: nil,
-			Expected: func(t *testing.T) {
-				// assert nothing was changed
-				assert.NotNil(t, formatter)
-				assert.Implements(t, (*format.Encoder)(nil), formatter)
-				assert.Equal(t, orig, formatter)
-			},
+			Name:     "Default",
+			Expected: "application/json",
+		},
+		{
+			Name:     "Unsupported",
+			Accept:   "application/xml",
+			Expected: "application/json",
 		},
 		{
-			Name:    "NewEncoder",
-			Encoder: xml.New(),
-			Expected: func(t *testing.T) {
-				// assert we changed to the XML formatter.
-				assert.NotNil(t, formatter)
-				assert.Implements(t, (*format.Encoder)(nil), formatter)
-				assert.NotEqual(t, orig, formatter)
-				assert.Equal(t, "application/xml", formatter.ContentType())
-			},
+			Name:     "Problem",
+			Accept:   ProblemXML,
+			Expected: "application/json",
+		},
+		{
+			Name:       "Context",
+			Accept:     "text/html, application/xml;q=0.9, application/json;q=0.5",
+			Middleware: []mux.MiddlewareFunc{middleware.WithEncoders(withXML)},
+			Expected:   "application/xml",
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.Name, func(st *testing.T) {
-			WithEncoder(tc.Encoder)
-			tc.Expected(st)
+			var (
+				ct string
+				e  format.Encoder
+			)
+			h := withMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+				ct, e = encoder(req.Context())
+			}), append(tc.Middleware, middleware.WithNegotiation())...)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", tc.Accept)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			require.NotNil(st, e)
+			assert.Equal(st, tc.Expected, ct)
+			assert.Equal(st, tc.Expected, e.ContentType())
 		})
 	}
 }
@@ -0,0 +1,279 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/requestid"
+)
+
+// defaultMaxEntries the default maximum amount of responses which are cached.
+const defaultMaxEntries = 10000
+
+// the warnings sent with a response served from the cache.
+const (
+	warningStale              = `110 - "Response is Stale"`
+	warningRevalidationFailed = `111 - "Revalidation Failed"`
+)
+
+// CacheOptions configures the response cache.
+type CacheOptions struct {
+	// TTL the duration a response is fresh for.
+	TTL time.Duration
+	// StaleWhileRevalidate the duration after a response becomes stale in which it is served
+	// while it is revalidated in the background.
+	StaleWhileRevalidate time.Duration
+	// StaleIfError the duration after a response becomes stale in which it is served when
+	// the response cannot be revalidated.
+	StaleIfError time.Duration
+	// MaxEntries the maximum amount of responses which are cached.
+	MaxEntries int
+	// Routes the route templates whose responses are cached, i.e /pokemon/{name}.
+	Routes []string
+}
+
+// Render a function which renders a response to the request defined by ctx, i.e by
+// wrapping a payload in the envelope of the request.
+type Render func(ctx context.Context, w http.ResponseWriter)
+
+// renderContextKey the context key to use for the render of a cached response.
+type renderContextKey struct{}
+
+// SetRender assigns the function which renders the response to the request, a cached
+// response is rendered for each request which it is served to, so that the body of the
+// response contains the request id of that request rather than the one it was cached for.
+// This is a no-op if the response is not cached.
+func SetRender(ctx context.Context, r Render) {
+	if p, ok := ctx.Value(renderContextKey{}).(*Render); ok {
+		*p = r
+	}
+}
+
+// cacheEntry a cached response.
+type cacheEntry struct {
+	code   int
+	header http.Header
+	body   []byte
+	render Render
+	stored time.Time
+}
+
+// age the duration between the response being stored and t.
+func (e *cacheEntry) age(t time.Time) time.Duration { return t.Sub(e.stored) }
+
+// write writes the cached response to the request defined by ctx, with the Age header at t
+// and any warning. The response is rendered for the request if the handler assigned a render,
+// otherwise it is written as it was recorded.
+func (e *cacheEntry) write(ctx context.Context, w http.ResponseWriter, t time.Time, warning string) {
+	for k, v := range e.header {
+		w.Header()[k] = v
+	}
+
+	w.Header().Set("Age", strconv.Itoa(int(e.age(t).Seconds())))
+	if warning != "" {
+		w.Header().Set("Warning", warning)
+	}
+
+	if e.render != nil {
+		e.render(ctx, w)
+		return
+	}
+
+	w.WriteHeader(e.code)
+	w.Write(e.body)
+}
+
+// responseCache stores the last good response for each representation.
+type responseCache struct {
+	mu           sync.Mutex
+	opts         CacheOptions
+	entries      map[string]*cacheEntry
+	revalidating map[string]bool
+}
+
+// get retrieves the cached response for key.
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+// set caches the response for key.
+func (c *responseCache) set(key string, e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// remove expired responses, then an arbitrary response, once the limit is reached.
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.opts.MaxEntries {
+		for k, v := range c.entries {
+			if v.age(e.stored) >= c.maxAge() {
+				delete(c.entries, k)
+			}
+		}
+
+		for k := range c.entries {
+			if len(c.entries) < c.opts.MaxEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = e
+}
+
+// maxAge the age after which a response can no longer be served.
+func (c *responseCache) maxAge() time.Duration {
+	max := c.opts.StaleWhileRevalidate
+	if c.opts.StaleIfError > max {
+		max = c.opts.StaleIfError
+	}
+	return c.opts.TTL + max
+}
+
+// startRevalidation marks the response for key as being revalidated, false is returned
+// if the response is already being revalidated.
+func (c *responseCache) startRevalidation(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.revalidating[key] {
+		return false
+	}
+	c.revalidating[key] = true
+	return true
+}
+
+// endRevalidation marks the response for key as revalidated.
+func (c *responseCache) endRevalidation(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.revalidating, key)
+}
+
+// fetch performs the request using next, the response is cached if it is successful.
+func (c *responseCache) fetch(next http.Handler, req *http.Request, key string) *httptest.ResponseRecorder {
+	var render Render
+	r := httptest.NewRecorder()
+	next.ServeHTTP(r, req.WithContext(context.WithValue(req.Context(), renderContextKey{}, &render)))
+
+	if cacheable(r) {
+		c.set(key, &cacheEntry{
+			code:   r.Code,
+			header: r.Header().Clone(),
+			body:   r.Body.Bytes(),
+			render: render,
+			stored: Now(req.Context()),
+		})
+	}
+	return r
+}
+
+// WithCache generates a middleware which caches the last good response of the configured
+// routes, keyed by the requested URI and the negotiated format and language.
+//
+// a response is fresh for the TTL, after which it is served with a Warning header while it
+// is revalidated in the background for the StaleWhileRevalidate duration. When the response
+// cannot be revalidated, i.e an upstream API fails with a 5xx, the stale response is served
+// with a Warning header instead of the error for the StaleIfError duration. Only responses
+// with a 2xx status code and no Warning header are cached, a handler sets a Warning header
+// when it has degraded the response. A cached response is rendered for each request using
+// the render assigned with SetRender, so the request id in the body matches the request,
+// otherwise it is served as it was recorded.
+func WithCache(opts CacheOptions) mux.MiddlewareFunc {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultMaxEntries
+	}
+
+	routes := make(map[string]bool, len(opts.Routes))
+	for _, r := range opts.Routes {
+		routes[r] = true
+	}
+
+	c := &responseCache{
+		opts:         opts,
+		entries:      make(map[string]*cacheEntry),
+		revalidating: make(map[string]bool),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodGet || !routes[routeTemplate(req)] {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			key := routeTemplate(req) + "\n" + req.URL.RequestURI() + "\n" +
+				req.Header.Get("Accept") + "\n" + req.Header.Get("Accept-Language")
+
+			t := Now(req.Context())
+			e, ok := c.get(key)
+			switch {
+			case ok && e.age(t) < opts.TTL:
+				e.write(req.Context(), w, t, "")
+				return
+			case ok && e.age(t) < opts.TTL+opts.StaleWhileRevalidate:
+				if c.startRevalidation(key) {
+					r := revalidation(req)
+					go func() {
+						defer c.endRevalidation(key)
+						c.fetch(next, r, key)
+					}()
+				}
+				e.write(req.Context(), w, t, warningStale)
+				return
+			}
+
+			r := c.fetch(next, req, key)
+			if failed(r) && ok && e.age(t) < opts.TTL+opts.StaleIfError {
+				e.write(req.Context(), w, t, warningRevalidationFailed)
+				return
+			}
+
+			for k, v := range r.Header() {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(r.Code)
+			w.Write(r.Body.Bytes())
+		})
+	}
+}
+
+// cacheable determines whether the recorded response is successful and can be cached.
+func cacheable(r *httptest.ResponseRecorder) bool {
+	return r.Code >= http.StatusOK && r.Code < http.StatusMultipleChoices && r.Header().Get("Warning") == ""
+}
+
+// failed determines whether the recorded response is a failure, in which case a stale
+// response is served instead. A client error, such as a 404, is not a failure.
+func failed(r *httptest.ResponseRecorder) bool {
+	return r.Code >= http.StatusInternalServerError || r.Header().Get("Warning") != ""
+}
+
+// revalidated the context values which are kept by the request which revalidates a response,
+// the values required to respond to the request in the same way.
+var revalidated = []interface{}{
+	loggerContextKey{}, encoderContextKey{}, clockContextKey{}, negotiationContextKey{},
+}
+
+// revalidation generates the request which revalidates a response in the background once req
+// has completed. The request is cloned onto a context which is never cancelled and only keeps
+// the values required to respond along with a new request id, so the calls made to the upstream
+// APIs are neither logged nor traced as part of req.
+func revalidation(req *http.Request) *http.Request {
+	ctx := context.Background()
+	for _, k := range revalidated {
+		if v := req.Context().Value(k); v != nil {
+			ctx = context.WithValue(ctx, k, v)
+		}
+	}
+
+	r := req.Clone(withRequestID(ctx, requestid.New()))
+	return mux.SetURLVars(r, mux.Vars(req))
+}
@@ -0,0 +1,254 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
+)
+
+func TestWithCache(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	var (
+		mu      sync.Mutex
+		current = start
+	)
+	now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return current
+	}
+	defer func() { now = time.Now }()
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		current = current.Add(d)
+	}
+
+	var (
+		calls   int
+		status  = http.StatusOK
+		warning string
+		body    = "first"
+	)
+	done := make(chan struct{}, 1)
+
+	m := mux.NewRouter()
+	m.Use(WithCache(CacheOptions{
+		TTL:                  time.Minute,
+		StaleWhileRevalidate: time.Minute,
+		StaleIfError:         time.Hour,
+		Routes:               []string{"/pokemon/{name}"},
+	}))
+	m.HandleFunc("/pokemon/{name}", func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		defer func() {
+			select {
+			case done <- struct{}{}:
+			default:
+			}
+		}()
+
+		if warning != "" {
+			w.Header().Set("Warning", warning)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+	m.HandleFunc("/status", func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+	})
+
+	do := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRecorder()
+		m.ServeHTTP(r, httptest.NewRequest(http.MethodGet, path, nil))
+		return r
+	}
+
+	r := do("/pokemon/pikachu")
+	<-done
+	assert.Equal(t, "first", r.Body.String())
+	assert.Empty(t, r.Header().Get("Age"))
+
+	t.Run("Fresh", func(st *testing.T) {
+		advance(30 * time.Second)
+		r := do("/pokemon/pikachu")
+		assert.Equal(st, http.StatusOK, r.Code)
+		assert.Equal(st, "first", r.Body.String())
+		assert.Equal(st, "30", r.Header().Get("Age"))
+		assert.Empty(st, r.Header().Get("Warning"))
+		assert.Equal(st, 1, calls)
+	})
+
+	t.Run("StaleWhileRevalidate", func(st *testing.T) {
+		advance(time.Minute)
+		body = "second"
+
+		r := do("/pokemon/pikachu")
+		assert.Equal(st, "first", r.Body.String())
+		assert.Equal(st, "90", r.Header().Get("Age"))
+		assert.Equal(st, warningStale, r.Header().Get("Warning"))
+
+		// the response is revalidated in the background.
+		<-done
+		require.Eventually(st, func() bool {
+			return do("/pokemon/pikachu").Body.String() == "second"
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(st, 2, calls)
+	})
+
+	t.Run("StaleIfError", func(st *testing.T) {
+		advance(10 * time.Minute)
+		status, body = http.StatusBadGateway, "upstream error"
+
+		r := do("/pokemon/pikachu")
+		<-done
+		assert.Equal(st, http.StatusOK, r.Code)
+		assert.Equal(st, "second", r.Body.String())
+		assert.Equal(st, "600", r.Header().Get("Age"))
+		assert.Equal(st, warningRevalidationFailed, r.Header().Get("Warning"))
+	})
+
+	t.Run("DegradedResponse", func(st *testing.T) {
+		status, body, warning = http.StatusOK, "untranslated", `199 - "Translation Failed"`
+
+		r := do("/pokemon/pikachu")
+		<-done
+		assert.Equal(st, "second", r.Body.String())
+		assert.Equal(st, warningRevalidationFailed, r.Header().Get("Warning"))
+	})
+
+	t.Run("ClientError", func(st *testing.T) {
+		status, body, warning = http.StatusNotFound, "not found", ""
+
+		// a client error is returned rather than the stale response.
+		r := do("/pokemon/pikachu")
+		<-done
+		assert.Equal(st, http.StatusNotFound, r.Code)
+		assert.Equal(st, "not found", r.Body.String())
+	})
+
+	t.Run("Expired", func(st *testing.T) {
+		advance(2 * time.Hour)
+		status, body = http.StatusBadGateway, "upstream error"
+
+		r := do("/pokemon/pikachu")
+		<-done
+		assert.Equal(st, http.StatusBadGateway, r.Code)
+		assert.Equal(st, "upstream error", r.Body.String())
+	})
+
+	t.Run("UncachedRoute", func(st *testing.T) {
+		before := calls
+		do("/status")
+		do("/status")
+		assert.Equal(st, before+2, calls)
+	})
+}
+
+func TestWithCache_Render(t *testing.T) {
+	var calls int
+
+	m := mux.NewRouter()
+	m.Use(WithRequestID(), WithCache(CacheOptions{TTL: time.Minute, Routes: []string{"/pokemon/{name}"}}))
+	m.HandleFunc("/pokemon/{name}", func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		render := func(ctx context.Context, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(RequestID(ctx) + ":" + mux.Vars(req)["name"]))
+		}
+		SetRender(req.Context(), render)
+		render(req.Context(), w)
+	})
+
+	do := func() *httptest.ResponseRecorder {
+		r := httptest.NewRecorder()
+		m.ServeHTTP(r, httptest.NewRequest(http.MethodGet, "/pokemon/pikachu", nil))
+		return r
+	}
+
+	first, second := do(), do()
+	assert.Equal(t, 1, calls)
+	assert.NotEqual(t, first.Header().Get(errors.RequestIDHeader), second.Header().Get(errors.RequestIDHeader))
+
+	// the cached response is rendered with the request id of each request.
+	for _, r := range []*httptest.ResponseRecorder{first, second} {
+		assert.Equal(t, r.Header().Get(errors.RequestIDHeader)+":pikachu", r.Body.String())
+	}
+	assert.NotEmpty(t, second.Header().Get("Age"))
+}
+
+// TestWithCache_Revalidation tests that a response is revalidated in the background using a
+// request which is independent of the request which triggered it.
+func TestWithCache_Revalidation(t *testing.T) {
+	current := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return current }
+
+	type seen struct {
+		id, name string
+		span     *trace.Span
+		language string
+		err      error
+	}
+	requests := make(chan seen, 2)
+
+	m := mux.NewRouter()
+	m.Use(
+		WithRequestID(), WithClock(clock), WithNegotiation(), WithTracing(trace.New(trace.Discard())),
+		WithCache(CacheOptions{TTL: time.Minute, StaleWhileRevalidate: time.Minute, Routes: []string{"/pokemon/{name}"}}),
+	)
+	m.HandleFunc("/pokemon/{name}", func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		requests <- seen{
+			id:       RequestID(ctx),
+			name:     mux.Vars(req)["name"],
+			span:     trace.SpanFromContext(ctx),
+			language: NegotiationFrom(ctx).AcceptLanguage,
+			err:      ctx.Err(),
+		}
+		w.Write([]byte("ok"))
+	})
+
+	do := func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/pokemon/pikachu", nil).WithContext(ctx)
+		req.Header.Set("Accept-Language", "de")
+		m.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	do()
+	first := <-requests
+	require.NotNil(t, first.span)
+
+	// the stale response is served, then revalidated once the request has completed.
+	current = current.Add(90 * time.Second)
+	do()
+	revalidated := <-requests
+
+	assert.NotEqual(t, first.id, revalidated.id)
+	assert.Nil(t, revalidated.span)
+	assert.NoError(t, revalidated.err)
+	assert.Equal(t, "pikachu", revalidated.name)
+	assert.Equal(t, "de", revalidated.language)
+}
+
+func TestSetRender(t *testing.T) {
+	// assigning a render to a request which is not cached is a no-op.
+	assert.NotPanics(t, func() {
+		SetRender(context.Background(), func(context.Context, http.ResponseWriter) {})
+	})
+}
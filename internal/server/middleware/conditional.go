@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultMaxValidators the default amount of representations whose modification time is tracked.
+const defaultMaxValidators = 10000
+
+// CacheControl the Cache-Control header to send with successful responses keyed by
+// route template, i.e /pokemon/{name}.
+type CacheControl map[string]string
+
+// ParseCacheControl parses a set of route=directives pairs, i.e
+// "/pokemon/{name}=public, max-age=86400", into a CacheControl.
+func ParseCacheControl(values []string) (CacheControl, error) {
+	cc := make(CacheControl, len(values))
+	for _, v := range values {
+		i := strings.Index(v, "=")
+		if i <= 0 || i == len(v)-1 {
+			return nil, errors.New("invalid cache control, expected route=directives: " + v)
+		}
+		cc[strings.TrimSpace(v[:i])] = strings.TrimSpace(v[i+1:])
+	}
+	return cc, nil
+}
+
+// validator the validators of a representation previously returned.
+type validator struct {
+	etag         string    // etag the entity tag of the representation.
+	lastModified time.Time // lastModified the time the representation was first returned.
+}
+
+// validators tracks the time each representation was first returned so that it can be
+// used as the Last-Modified time, as the upstream APIs do not supply one.
+type validators struct {
+	mu  sync.Mutex
+	max int
+	m   map[string]validator
+}
+
+// lastModified retrieves the time the representation identified by key with the
+// supplied entity tag was first returned, t is used if it has not been returned before.
+func (v *validators) lastModified(key, etag string, t time.Time) time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if e, ok := v.m[key]; ok && e.etag == etag {
+		return e.lastModified
+	}
+
+	// evict an arbitrary representation once the limit is reached.
+	if len(v.m) >= v.max {
+		for k := range v.m {
+			delete(v.m, k)
+			break
+		}
+	}
+
+	e := validator{etag: etag, lastModified: t.UTC().Truncate(time.Second)}
+	v.m[key] = e
+	return e.lastModified
+}
+
+// now the function used to retrieve the current time when no clock is assigned to the
+// request context, this can be overridden in tests.
+var now = time.Now
+
+// WithConditional generates a middleware which supports conditional GET requests.
+//
+// a strong ETag is computed from the encoded body of each successful response, unless the
+// handler has already set one, i.e when the body contains a per-request value such as the
+// request id. The Last-Modified time is the time the representation was first returned.
+// A request with a matching If-None-Match, or when absent an If-Modified-Since which is not
+// before the Last-Modified time, receives a 304 without a body. The Cache-Control defined for the
+// route, if any, is sent with successful responses so that they can be cached by a CDN.
+func WithConditional(cc CacheControl) mux.MiddlewareFunc {
+	v := &validators{max: defaultMaxValidators, m: make(map[string]validator)}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			r := httptest.NewRecorder()
+			next.ServeHTTP(r, req)
+
+			for k, vv := range r.Header() {
+				w.Header()[k] = vv
+			}
+
+			if r.Code != http.StatusOK {
+				w.WriteHeader(r.Code)
+				w.Write(r.Body.Bytes())
+				return
+			}
+
+			// the representation varies on the negotiated format and language.
+			key := req.URL.RequestURI() + "\n" + req.Header.Get("Accept") + "\n" + req.Header.Get("Accept-Language")
+			etag := r.Header().Get("ETag")
+			if etag == "" {
+				etag = EntityTag(r.Body.Bytes())
+			}
+			lm := v.lastModified(key, etag, Now(req.Context()))
+
+			h := w.Header()
+			h.Set("ETag", etag)
+			h.Set("Last-Modified", lm.Format(http.TimeFormat))
+			h.Add("Vary", "Accept, Accept-Language")
+			if c, ok := cc[routeTemplate(req)]; ok {
+				h.Set("Cache-Control", c)
+			}
+
+			if notModified(req, etag, lm) {
+				h.Del("Content-Type")
+				h.Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.WriteHeader(r.Code)
+			w.Write(r.Body.Bytes())
+		})
+	}
+}
+
+// EntityTag computes a strong entity tag from an encoded representation.
+func EntityTag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified evaluates the preconditions of the request against the validators of the
+// representation, If-Modified-Since is ignored when If-None-Match is present.
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		return etagMatch(inm, etag)
+	}
+
+	ims := req.Header.Get("If-Modified-Since")
+	if ims == "" {
+		return false
+	}
+
+	t, err := http.ParseTime(ims)
+	return err == nil && !lastModified.After(t)
+}
+
+// etagMatch determines whether any of the entity tags in an If-None-Match header
+// matches etag, using the weak comparison.
+func etagMatch(header, etag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	cc, err := ParseCacheControl([]string{"/pokemon/{name}=public, max-age=86400", " /status = no-store "})
+	require.NoError(t, err)
+	assert.Equal(t, CacheControl{"/pokemon/{name}": "public, max-age=86400", "/status": "no-store"}, cc)
+
+	for _, v := range []string{"/status", "=no-store", "/status="} {
+		_, err := ParseCacheControl([]string{v})
+		assert.Error(t, err, v)
+	}
+}
+
+func TestWithConditional(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+	defer func() { now = time.Now }()
+
+	body := "pikachu"
+	m := mux.NewRouter()
+	m.Use(
+		WithLogger(fmt.New(fmt.LevelNone)),
+		WithConditional(CacheControl{"/pokemon/{name}": "public, max-age=60"}),
+	)
+	m.HandleFunc("/pokemon/{name}", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	m.HandleFunc("/missing", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	m.HandleFunc("/tagged", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("ETag", `"tagged"`)
+		w.Write([]byte(time.Now().String()))
+	})
+
+	do := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		m.ServeHTTP(r, req)
+		return r
+	}
+
+	r := do("/pokemon/pikachu", nil)
+	require.Equal(t, http.StatusOK, r.Code)
+	etag := r.Header().Get("ETag")
+	assert.Equal(t, EntityTag([]byte(body)), etag)
+	assert.Equal(t, start.Format(http.TimeFormat), r.Header().Get("Last-Modified"))
+	assert.Equal(t, "public, max-age=60", r.Header().Get("Cache-Control"))
+	assert.Equal(t, "Accept, Accept-Language", r.Header().Get("Vary"))
+	assert.Equal(t, body, r.Body.String())
+
+	tt := []struct {
+		Name     string
+		Headers  map[string]string
+		Expected int
+	}{
+		{Name: "IfNoneMatch", Headers: map[string]string{"If-None-Match": etag}, Expected: http.StatusNotModified},
+		{Name: "IfNoneMatchWeak", Headers: map[string]string{"If-None-Match": `"other", W/` + etag}, Expected: http.StatusNotModified},
+		{Name: "IfNoneMatchAny", Headers: map[string]string{"If-None-Match": "*"}, Expected: http.StatusNotModified},
+		{Name: "IfNoneMatchChanged", Headers: map[string]string{"If-None-Match": `"other"`}, Expected: http.StatusOK},
+		{
+			Name:     "IfModifiedSince",
+			Headers:  map[string]string{"If-Modified-Since": start.Format(http.TimeFormat)},
+			Expected: http.StatusNotModified,
+		},
+		{
+			Name:     "ModifiedSince",
+			Headers:  map[string]string{"If-Modified-Since": start.Add(-time.Hour).Format(http.TimeFormat)},
+			Expected: http.StatusOK,
+		},
+		{
+			Name: "IfNoneMatchPrecedence",
+			Headers: map[string]string{
+				"If-None-Match":     `"other"`,
+				"If-Modified-Since": start.Format(http.TimeFormat),
+			},
+			Expected: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			r := do("/pokemon/pikachu", tc.Headers)
+			assert.Equal(st, tc.Expected, r.Code)
+			assert.Equal(st, etag, r.Header().Get("ETag"))
+			assert.Equal(st, "public, max-age=60", r.Header().Get("Cache-Control"))
+			if tc.Expected == http.StatusNotModified {
+				assert.Empty(st, r.Body.String())
+				assert.Empty(st, r.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(st, body, r.Body.String())
+			}
+		})
+	}
+
+	t.Run("Changed", func(st *testing.T) {
+		now = func() time.Time { return start.Add(time.Hour) }
+		body = "raichu"
+
+		r := do("/pokemon/pikachu", map[string]string{"If-None-Match": etag})
+		assert.Equal(st, http.StatusOK, r.Code)
+		assert.Equal(st, EntityTag([]byte(body)), r.Header().Get("ETag"))
+		assert.Equal(st, start.Add(time.Hour).Format(http.TimeFormat), r.Header().Get("Last-Modified"))
+	})
+
+	t.Run("Error", func(st *testing.T) {
+		r := do("/missing", nil)
+		assert.Equal(st, http.StatusNotFound, r.Code)
+		assert.Empty(st, r.Header().Get("ETag"))
+		assert.Empty(st, r.Header().Get("Cache-Control"))
+	})
+
+	t.Run("HandlerETag", func(st *testing.T) {
+		r := do("/tagged", map[string]string{"If-None-Match": `"tagged"`})
+		assert.Equal(st, http.StatusNotModified, r.Code)
+		assert.Empty(st, r.Header().Get("Cache-Control"))
+	})
+}
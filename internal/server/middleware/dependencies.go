@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/format"
+)
+
+// the context keys to use for the dependencies of a handler.
+type (
+	clockContextKey   struct{}
+	encoderContextKey struct{}
+)
+
+// Clock a function which retrieves the current time.
+type Clock func() time.Time
+
+// WithClock middleware function which assigns the clock used to retrieve the current time,
+// i.e by the cache, to the request context.
+func WithClock(c Clock) mux.MiddlewareFunc {
+	return withValue(clockContextKey{}, c)
+}
+
+// Now retrieves the current time from the clock assigned to the supplied context, the
+// system clock is used if no clock is assigned.
+func Now(ctx context.Context) time.Time {
+	if c, ok := ctx.Value(clockContextKey{}).(Clock); ok && c != nil {
+		return c()
+	}
+	return now()
+}
+
+// WithEncoders middleware function which assigns the encoders used to encode responses,
+// keyed by content type, to the request context.
+func WithEncoders(e map[string]format.Encoder) mux.MiddlewareFunc {
+	return withValue(encoderContextKey{}, e)
+}
+
+// Encoders retrieves the encoders assigned to the supplied context keyed by content type,
+// false is returned if no encoders are assigned.
+func Encoders(ctx context.Context) (map[string]format.Encoder, bool) {
+	e, ok := ctx.Value(encoderContextKey{}).(map[string]format.Encoder)
+	return e, ok && len(e) > 0
+}
+
+// withValue generates a middleware which assigns the value to the request context.
+func withValue(key, value interface{}) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), key, value)))
+		})
+	}
+}
@@ -29,16 +29,24 @@ func WithLogger(l log.Logger) mux.MiddlewareFunc {
 			next.ServeHTTP(r, req.WithContext(withLogger(req.Context(), l)))
 			l.Debugf("%s: %s - %d - %s", req.Method, req.URL.Path, r.Code, time.Since(t).String())
 
-			// duplicate the response into the expecting writer.
+			// duplicate the response into the expecting writer, the headers
+			// have to be copied before the status code is written.
+			for k, v := range r.Header() {
+				w.Header()[k] = v
+			}
 			w.WriteHeader(r.Code)
-			for k := range r.Header() {
-				w.Header().Set(k, r.Header().Get(k))
+			if r.Body.Len() > 0 && bodyAllowed(r.Code) {
+				w.Write(r.Body.Bytes())
 			}
-			w.Write(r.Body.Bytes())
 		})
 	}
 }
 
+// bodyAllowed determines whether a response with the supplied status code can have a body.
+func bodyAllowed(code int) bool {
+	return code >= http.StatusOK && code != http.StatusNoContent && code != http.StatusNotModified
+}
+
 // withLogger assigns a logger into a context.
 func withLogger(ctx context.Context, l log.Logger) context.Context {
 	return context.WithValue(ctx, loggerContextKey{}, l)
@@ -83,3 +83,19 @@ func TestLogger(t *testing.T) {
 		Logger(context.Background())
 	})
 }
+
+func TestWithLogger_NotModified(t *testing.T) {
+	h := WithLogger(fmt.New(fmt.LevelNone)).Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusNotModified)
+	}))
+
+	r := httptest.NewRecorder()
+	h.ServeHTTP(r, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	// the headers must be written before the status code.
+	res := r.Result()
+	assert.Equal(t, http.StatusNotModified, res.StatusCode)
+	assert.Equal(t, `"etag"`, res.Header.Get("ETag"))
+	assert.Zero(t, r.Body.Len())
+}
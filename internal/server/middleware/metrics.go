@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/metrics"
+)
+
+// unknownRoute the route label used when the route template cannot be determined.
+const unknownRoute = "unknown"
+
+// statusRecorder a http.ResponseWriter which records the status code written.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int // code the written status code.
+}
+
+// WriteHeader implements http.ResponseWriter interface.
+// records the status code before writing it to the wrapped writer.
+func (s *statusRecorder) WriteHeader(code int) {
+	s.code = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// WithMetrics generates a middleware which records metrics on each request handled by
+// the server into the supplied registry. The following metrics are recorded:
+//
+// - http_requests_total the amount of requests handled by method, route and status code.
+// - http_request_duration_seconds a histogram of the duration of each request by method and route.
+//
+// the route is the route template, i.e /pokemon/{name}, rather than the requested path
+// so that the amount of series recorded is bounded.
+func WithMetrics(r metrics.Registry) mux.MiddlewareFunc {
+	requests := r.Counter("http_requests_total",
+		"The amount of requests handled by the server.", "method", "route", "status")
+	latency := r.Histogram("http_request_duration_seconds",
+		"The duration of each request handled by the server.", nil, "method", "route")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			route := routeTemplate(req)
+			sr := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+			t := time.Now()
+			next.ServeHTTP(sr, req)
+
+			requests.Inc(req.Method, route, strconv.Itoa(sr.code))
+			latency.Observe(time.Since(t).Seconds(), req.Method, route)
+		})
+	}
+}
+
+// routeTemplate retrieves the template of the route matched for the request, i.e /pokemon/{name}.
+func routeTemplate(req *http.Request) string {
+	if cr := mux.CurrentRoute(req); cr != nil {
+		if tpl, err := cr.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return unknownRoute
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/metrics"
+)
+
+func TestWithMetrics(t *testing.T) {
+	p := metrics.NewPrometheus()
+	m := mux.NewRouter()
+	m.Use(WithMetrics(p))
+	m.HandleFunc("/pokemon/{name}", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	m.HandleFunc("/status", func(w http.ResponseWriter, req *http.Request) {})
+
+	for _, path := range []string{"/pokemon/mewtwo", "/pokemon/ditto", "/status"} {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	b := &bytes.Buffer{}
+	_, err := p.WriteTo(b)
+	require.NoError(t, err)
+
+	// the route template is used rather than the path.
+	assert.Contains(t, b.String(), `http_requests_total{method="GET",route="/pokemon/{name}",status="404"} 2`)
+	assert.Contains(t, b.String(), `http_requests_total{method="GET",route="/status",status="200"} 1`)
+	assert.Contains(t, b.String(), `http_request_duration_seconds_count{method="GET",route="/pokemon/{name}"} 2`)
+}
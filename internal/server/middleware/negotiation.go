@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/redact"
+)
+
+// negotiationContextKey the context key to use for the negotiation details.
+type negotiationContextKey struct{}
+
+// Negotiation the details of a request which are used to negotiate the response.
+type Negotiation struct {
+	Accept         string // Accept the Accept header sent with the request.
+	AcceptLanguage string // AcceptLanguage the Accept-Language header sent with the request.
+	Instance       string // Instance the requested URI, with any secrets redacted.
+}
+
+// WithNegotiation middleware function which assigns the details of the request
+// used to negotiate the response, i.e the format and language of an error, to the request context.
+func WithNegotiation() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			n := Negotiation{
+				Accept:         req.Header.Get("Accept"),
+				AcceptLanguage: req.Header.Get("Accept-Language"),
+				Instance:       redact.Default().URL(req.URL.RequestURI()),
+			}
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), negotiationContextKey{}, n)))
+		})
+	}
+}
+
+// NegotiationFrom retrieves the negotiation details from the supplied context
+// the zero value is returned if no details are assigned.
+func NegotiationFrom(ctx context.Context) Negotiation {
+	n, _ := ctx.Value(negotiationContextKey{}).(Negotiation)
+	return n
+}
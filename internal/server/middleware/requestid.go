@@ -2,35 +2,96 @@ package middleware
 
 import (
 	"context"
+	"net"
 	"net/http"
 
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 
 	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/requestid"
 )
 
-// requestIDContextKey the context key to use for the request id.
-type requestIDContextKey struct{}
+// Trust a function which determines whether the request id sent with a request can be trusted.
+type Trust func(req *http.Request) bool
 
-// WithRequestID middleware function which generates a new request id
-// assigns is to the request context and sets it as a response header.
-func WithRequestID() mux.MiddlewareFunc {
+// TrustAll trusts the request id sent with every request.
+func TrustAll() Trust {
+	return func(*http.Request) bool { return true }
+}
+
+// TrustNetworks trusts the request id sent with requests from any of the supplied networks
+// in CIDR notation, i.e the network our gateway is deployed in.
+func TrustNetworks(cidrs ...string) (Trust, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+
+	return func(req *http.Request) bool {
+		host, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			host = req.RemoteAddr
+		}
+
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return false
+		}
+
+		for _, n := range nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// WithRequestID middleware function which assigns a request id to the request
+// context and sets it as a response header.
+//
+// the request id sent with the request is used if it is valid and it is trusted by
+// any of the supplied trust functions, otherwise a new request id is generated. The
+// request id is used to derive the request ids sent to any upstream API.
+func WithRequestID(trust ...Trust) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-			id := uuid.New().String()
+			id := req.Header.Get(errors.RequestIDHeader)
+			if !requestid.Valid(id) || !trusted(req, trust) {
+				id = requestid.New()
+			}
+
 			w.Header().Set(errors.RequestIDHeader, id)
 			next.ServeHTTP(w, req.WithContext(withRequestID(req.Context(), id)))
 		})
 	}
 }
 
+// trusted determines whether any of the trust functions trusts the request.
+func trusted(req *http.Request, trust []Trust) bool {
+	for _, t := range trust {
+		if t != nil && t(req) {
+			return true
+		}
+	}
+	return false
+}
+
 // withRequestID generates a context with the request id assigned as a value.
 func withRequestID(ctx context.Context, id string) context.Context {
-	return context.WithValue(ctx, requestIDContextKey{}, id)
+	return requestid.NewContext(ctx, id)
 }
 
 // RequestID attempts to retrieve the request id from the supplied context.
+// this panics if there is no request id assigned to the context.
 func RequestID(ctx context.Context) string {
-	return ctx.Value(requestIDContextKey{}).(string)
+	id, ok := requestid.FromContext(ctx)
+	if !ok {
+		panic("middleware: no request id assigned to the context")
+	}
+	return id
 }
@@ -59,3 +59,85 @@ func TestWithRequestID(t *testing.T) {
 		RequestID(context.Background())
 	})
 }
+
+func TestRequestID_Inbound(t *testing.T) {
+	gateway, err := TrustNetworks("10.0.0.0/8")
+	require.NoError(t, err)
+
+	_, err = TrustNetworks("invalid")
+	assert.Error(t, err)
+
+	tt := []struct {
+		Name       string
+		RemoteAddr string
+		ID         string
+		Trust      []Trust
+		Expected   func(t *testing.T, id string)
+	}{
+		{
+			Name:       "Trusted",
+			RemoteAddr: "10.1.2.3:1234",
+			ID:         "gateway-id",
+			Trust:      []Trust{gateway},
+			Expected: func(t *testing.T, id string) {
+				assert.Equal(t, "gateway-id", id)
+			},
+		},
+		{
+			Name:       "TrustAll",
+			RemoteAddr: "192.168.0.1:1234",
+			ID:         "gateway-id",
+			Trust:      []Trust{TrustAll()},
+			Expected: func(t *testing.T, id string) {
+				assert.Equal(t, "gateway-id", id)
+			},
+		},
+		{
+			Name:       "Untrusted",
+			RemoteAddr: "192.168.0.1:1234",
+			ID:         "gateway-id",
+			Trust:      []Trust{gateway},
+			Expected: func(t *testing.T, id string) {
+				_, err := uuid.Parse(id)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			Name:       "NoTrust",
+			RemoteAddr: "10.1.2.3:1234",
+			ID:         "gateway-id",
+			Expected: func(t *testing.T, id string) {
+				_, err := uuid.Parse(id)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			Name:       "Invalid",
+			RemoteAddr: "10.1.2.3:1234",
+			ID:         "gateway id\twith whitespace",
+			Trust:      []Trust{gateway},
+			Expected: func(t *testing.T, id string) {
+				_, err := uuid.Parse(id)
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			var found string
+			h := WithRequestID(tc.Trust...).Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				found = RequestID(req.Context())
+			}))
+
+			r := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/status", nil)
+			req.RemoteAddr = tc.RemoteAddr
+			req.Header.Set(errors.RequestIDHeader, tc.ID)
+			h.ServeHTTP(r, req)
+
+			assert.Equal(t, found, r.Header().Get(errors.RequestIDHeader))
+			tc.Expected(t, found)
+		})
+	}
+}
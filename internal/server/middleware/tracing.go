@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/redact"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
+)
+
+// WithTracing generates a middleware which starts a span for each request handled by the server.
+//
+// the span continues the trace supplied in the traceparent and tracestate headers, if the headers
+// are missing or invalid a new trace is started. The span is assigned to the request context so
+// any calls made to an upstream API from the handler are traced as children of the span. The
+// traceparent of the span is returned in the response so the trace can be found.
+func WithTracing(t *trace.Tracer) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+			if sc, ok := trace.Extract(req.Header); ok {
+				ctx = trace.ContextWithRemote(ctx, sc)
+			}
+
+			route := routeTemplate(req)
+			ctx, s := t.Start(ctx, req.Method+" "+route)
+			s.SetAttribute("http.method", req.Method)
+			s.SetAttribute("http.route", route)
+			s.SetAttribute("http.target", redact.Default().URL(req.URL.RequestURI()))
+			w.Header().Set(trace.TraceparentHeader, s.Context.Traceparent())
+
+			sr := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+			next.ServeHTTP(sr, req.WithContext(ctx))
+
+			s.SetAttribute("http.status_code", strconv.Itoa(sr.code))
+			s.Finish()
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
+)
+
+func TestWithTracing(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tt := []struct {
+		Name        string
+		Traceparent string
+		Expected    func(t *testing.T, s *trace.Span, w *httptest.ResponseRecorder)
+	}{
+		{
+			Name:        "ContinueTrace",
+			Traceparent: traceparent,
+			Expected: func(t *testing.T, s *trace.Span, w *httptest.ResponseRecorder) {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", s.TraceID)
+				assert.Equal(t, "00f067aa0ba902b7", s.ParentID)
+				assert.Equal(t, s.Context.Traceparent(), w.Header().Get(trace.TraceparentHeader))
+			},
+		},
+		{
+			Name:        "NewTrace",
+			Traceparent: "invalid",
+			Expected: func(t *testing.T, s *trace.Span, w *httptest.ResponseRecorder) {
+				assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", s.TraceID)
+				assert.Empty(t, s.ParentID)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			e := trace.NewMemory()
+			var inner *trace.Span
+
+			m := mux.NewRouter()
+			m.Use(WithTracing(trace.New(e)))
+			m.HandleFunc("/pokemon/{name}", func(w http.ResponseWriter, req *http.Request) {
+				inner = trace.SpanFromContext(req.Context())
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/pokemon/ditto", nil)
+			req.Header.Set(trace.TraceparentHeader, tc.Traceparent)
+			m.ServeHTTP(w, req)
+
+			spans := e.Spans()
+			require.Len(t, spans, 1)
+			s := spans[0]
+			assert.Equal(t, inner, s)
+			assert.Equal(t, "GET /pokemon/{name}", s.Name)
+			assert.Equal(t, "404", s.Attributes["http.status_code"])
+			tc.Expected(t, s, w)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"github.com/gorilla/mux"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/format"
+	"github.com/jacklaaa89/pokeapi/internal/api/log"
+	"github.com/jacklaaa89/pokeapi/internal/api/metrics"
+	"github.com/jacklaaa89/pokeapi/internal/api/trace"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+	"github.com/jacklaaa89/pokeapi/internal/server/rules"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// Option configures the dependencies of a server.
+type Option interface {
+	apply(*Server) // apply allows us to apply settings to a Server instance.
+}
+
+// funcOption wraps a function that modifies a Server into an
+// implementation of the Option interface.
+type funcOption struct {
+	f func(*Server) // f is the wrapped function.
+}
+
+// apply implements Option interface.
+func (fo *funcOption) apply(s *Server) { fo.f(s) }
+
+// newOption generates a new Option from a function.
+func newOption(f func(*Server)) Option { return &funcOption{f: f} }
+
+// WithRules sets the rules used to choose the translation method for a species,
+// the default rules are used if not supplied.
+func WithRules(e *rules.Engine) Option {
+	return newOption(func(s *Server) {
+		if e == nil {
+			return
+		}
+		s.rules = e
+	})
+}
+
+// WithMethods sets the registry of the translation methods which are available, which are
+// served from /translations and can be supplied using ?method=, every registered translation
+// method is available if not supplied.
+func WithMethods(r translation.Registry) Option {
+	return newOption(func(s *Server) {
+		if r == nil {
+			return
+		}
+		s.methods = r
+	})
+}
+
+// WithQuota sets the function used to retrieve the translation API quota served from
+// /status/translation, i.e translation.Client.Quota.
+func WithQuota(fn func() translation.Quota) Option {
+	return newOption(func(s *Server) {
+		if fn == nil {
+			return
+		}
+		s.quota = fn
+	})
+}
+
+// WithLogger sets the logger assigned to each request, which also performs access logging.
+func WithLogger(l log.Logger) Option {
+	return newOption(func(s *Server) {
+		if l == nil {
+			return
+		}
+		s.logger = l
+	})
+}
+
+// WithEncoder sets the encoder used to encode responses of the content type, the response is
+// encoded using the encoder of the content type the client prefers. Responses are encoded as
+// JSON and problems as JSON or XML by default, see helpers.DefaultEncoders.
+func WithEncoder(contentType string, e format.Encoder) Option {
+	return newOption(func(s *Server) {
+		if contentType == "" || e == nil {
+			return
+		}
+		s.encoders[contentType] = e
+	})
+}
+
+// WithClock sets the clock used to retrieve the current time, i.e by the response cache.
+func WithClock(c middleware.Clock) Option {
+	return newOption(func(s *Server) {
+		if c == nil {
+			return
+		}
+		s.clock = c
+	})
+}
+
+// WithMetrics sets the registry the requests are recorded in, which is served from /metrics,
+// i.e the registry of the API clients so that the metrics of their calls are also served. A
+// new registry is used if not supplied.
+func WithMetrics(r *metrics.Prometheus) Option {
+	return newOption(func(s *Server) {
+		if r == nil {
+			return
+		}
+		s.metrics = r
+	})
+}
+
+// WithTracer sets the tracer used to trace each request, i.e the tracer of the API clients so
+// that their calls are traced as children of the request. The spans are discarded if not supplied.
+func WithTracer(t *trace.Tracer) Option {
+	return newOption(func(s *Server) {
+		if t == nil {
+			return
+		}
+		s.tracer = t
+	})
+}
+
+// WithTrust appends to the trust functions which determine whether the request id sent
+// with a request is used, a new request id is generated for every request by default.
+func WithTrust(t ...middleware.Trust) Option {
+	return newOption(func(s *Server) {
+		s.trust = append(s.trust, t...)
+	})
+}
+
+// WithMiddleware appends to the middleware applied to each request, in the order supplied.
+func WithMiddleware(m ...mux.MiddlewareFunc) Option {
+	return newOption(func(s *Server) {
+		s.middleware = append(s.middleware, m...)
+	})
+}
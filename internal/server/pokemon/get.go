@@ -5,17 +5,18 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/jacklaaa89/pokeapi/internal/pokeapi"
 	"github.com/jacklaaa89/pokeapi/internal/server/helpers"
 	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
 )
 
 // Get http.HandlerFunc which handles /pokemon/{name}
-func Get(w http.ResponseWriter, req *http.Request) {
+func (h *Handler) Get(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 	l := middleware.Logger(ctx)
 
-	v := getVars(req)
-	res, err := get(ctx, v["name"])
+	v := h.vars(req)
+	res, err := h.get(ctx, v["name"])
 	if err != nil {
 		l.Errorf(err.Error())
 		helpers.RespondError(ctx, w, err)
@@ -26,15 +27,20 @@ func Get(w http.ResponseWriter, req *http.Request) {
 }
 
 // get attempts to retrieve details for a pokemon based on the name supplied.
-func get(ctx context.Context, name string) (*SpeciesResponse, error) {
-	if name == "" {
-		return nil, helpers.InvalidRequest(errors.New("pokemon name is required"))
-	}
-
-	s, err := pokemonAPI.Pokemon.Species(ctx, name)
+func (h *Handler) get(ctx context.Context, name string) (*SpeciesResponse, error) {
+	s, err := h.getSpecies(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 
 	return fromSpecies(s), nil
 }
+
+// getSpecies attempts to retrieve the species for a pokemon based on the name supplied.
+func (h *Handler) getSpecies(ctx context.Context, name string) (*pokeapi.Species, error) {
+	if name == "" {
+		return nil, helpers.InvalidRequest(errors.New("pokemon name is required"))
+	}
+
+	return h.species.Species(ctx, name)
+}
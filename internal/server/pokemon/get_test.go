@@ -25,16 +25,20 @@ type partialMockAPI interface {
 	AllExpectationsMet() error
 }
 
-// setup performs the test setup, sorting out the API clients
-// to use mock API's.
-func setup(fn func(m mock.API)) partialMockAPI {
+// setup performs the test setup, initialising a handler whose API clients
+// use the mock API.
+func setup(fn func(m mock.API)) (partialMockAPI, *Handler) {
 	m := mock.NewMockAPI(formatter)
 	fn(m)
 	m.Start()
 
-	pokemonAPI = pokeapi.NewWithEndpoint(m.URL())
-	translationAPI = translation.NewWithEndpoint(m.URL(), "")
-	return m
+	h := NewHandler(
+		pokeapi.NewWithEndpoint(m.URL()).Pokemon,
+		translation.NewWithEndpoint(m.URL(), ""),
+		nil,
+		nil,
+	)
+	return m, h
 }
 
 // withMiddleware wraps the supplied http.handler with all of the middlewares
@@ -115,7 +119,7 @@ func TestGet(t *testing.T) {
 
 	for _, tc := range tt {
 		t.Run(tc.Name, func(st *testing.T) {
-			p := setup(tc.Setup)
+			p, h := setup(tc.Setup)
 			defer p.Close()
 
 			v := tc.Vars
@@ -124,7 +128,7 @@ func TestGet(t *testing.T) {
 			}
 
 			// set up the function to get the URL parameters for tests.
-			getVars = func(*http.Request) map[string]string {
+			h.vars = func(*http.Request) map[string]string {
 				return v
 			}
 
@@ -139,7 +143,7 @@ func TestGet(t *testing.T) {
 				middleware.WithRequestID(),
 			}
 
-			withMiddleware(http.HandlerFunc(Get), ml...).ServeHTTP(w, req)
+			withMiddleware(http.HandlerFunc(h.Get), ml...).ServeHTTP(w, req)
 
 			tc.Expected(st, w)
 			assert.NoError(st, p.AllExpectationsMet())
@@ -2,43 +2,154 @@ package pokemon
 
 import (
 	"context"
+	_errors "errors"
 	"net/http"
+	"strconv"
 
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/pokeapi"
 	"github.com/jacklaaa89/pokeapi/internal/server/helpers"
-
 	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
-
+	"github.com/jacklaaa89/pokeapi/internal/server/rules"
 	"github.com/jacklaaa89/pokeapi/internal/translation"
 )
 
 // Translated http.HandlerFunc which handles the /pokemon/{name}/translated endpoint.
-func Translated(w http.ResponseWriter, req *http.Request) {
+//
+// the translation method is chosen using the translation rules, unless ?method= is supplied.
+// the description is returned untranslated if the translation fails, unless ?strict=true
+// is supplied in which case the error is returned instead.
+func (h *Handler) Translated(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 	l := middleware.Logger(ctx)
 
-	v := getVars(req)
-	res, err := get(ctx, v["name"])
+	strict, err := strictParam(req)
+	if err != nil {
+		helpers.RespondError(ctx, w, err)
+		return
+	}
+
+	method, err := h.methodParam(req)
+	if err != nil {
+		helpers.RespondError(ctx, w, err)
+		return
+	}
+
+	v := h.vars(req)
+	s, err := h.getSpecies(ctx, v["name"])
 	if err != nil {
 		l.Errorf(err.Error())
 		helpers.RespondError(ctx, w, err)
 		return
 	}
 
-	applyTranslation(ctx, res)
+	d := rules.Decision{Method: method, Rule: ruleRequest}
+	if method == 0 {
+		d = h.chooseMethod(ctx, s)
+	}
+
+	res := fromSpecies(s)
+	if err := h.applyTranslation(ctx, res, d); err != nil {
+		l.Warnf("could not translate description: %v", err)
+		if strict {
+			helpers.RespondError(ctx, w, err)
+			return
+		}
+
+		// the description is returned untranslated, the warning stops the degraded
+		// response from replacing the last good response in the response cache.
+		w.Header().Set("Warning", warningTranslationFailed)
+	}
 	helpers.RespondOK(ctx, w, res)
 }
 
-// applyTranslation applies the translation to the description
-// if an error occurs performing the translation, the original description
-// is returned.
-func applyTranslation(ctx context.Context, sr *SpeciesResponse) {
-	method := translation.Shakespeare
-	if sr.Habitat == "cave" || sr.IsLegendary {
-		method = translation.Yoda
+// strictParam retrieves the strict query parameter, which defaults to false.
+func strictParam(req *http.Request) (bool, error) {
+	v := req.URL.Query().Get("strict")
+	if v == "" {
+		return false, nil
 	}
 
-	out, err := translationAPI.Translate(ctx, sr.Description, method)
-	if err == nil {
-		sr.Description = out
+	strict, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, helpers.InvalidRequest(_errors.New("strict must be a boolean"))
+	}
+	return strict, nil
+}
+
+// ruleRequest the rule reported when the translation method is supplied with the request.
+const ruleRequest = "request"
+
+// methodParam retrieves the method query parameter from the available translation methods,
+// zero is returned if it is not supplied.
+func (h *Handler) methodParam(req *http.Request) (translation.Method, error) {
+	v := req.URL.Query().Get("method")
+	if v == "" {
+		return 0, nil
+	}
+
+	m, err := h.methods.ParseMethod(v)
+	if err != nil {
+		return 0, helpers.InvalidRequest(err)
+	}
+	return m, nil
+}
+
+// chooseMethod chooses the translation method for the species using the translation rules.
+// the types of the species are only retrieved when a rule requires them, a species without
+// types does not match any rule with a condition on the type.
+func (h *Handler) chooseMethod(ctx context.Context, s *pokeapi.Species) rules.Decision {
+	attrs := rules.Species{
+		Habitat:    name(s.Habitat),
+		Legendary:  s.IsLegendary,
+		Mythical:   s.IsMythical,
+		Color:      name(s.Color),
+		Generation: name(s.Generation),
+	}
+
+	if h.rules.NeedsTypes() {
+		p, err := h.species.Pokemon(ctx, s.DefaultVariety())
+		if err != nil {
+			middleware.Logger(ctx).Warnf("could not retrieve the types of %s: %v", s.Name, err)
+		} else {
+			attrs.Types = p.TypeNames()
+		}
+	}
+	return h.rules.Choose(attrs)
+}
+
+// name retrieves the name of a resource, an empty string is returned if the resource is nil.
+func name(r *pokeapi.NamedAPIResource) string {
+	if r == nil {
+		return ""
+	}
+	return r.Name
+}
+
+// warningTranslationFailed the warning sent when the description could not be translated.
+const warningTranslationFailed = `199 - "Translation Failed"`
+
+// applyTranslation applies the chosen translation to the description and records the
+// outcome in the response. if an error occurs performing the translation, the
+// original description is kept and the error is returned.
+func (h *Handler) applyTranslation(ctx context.Context, sr *SpeciesResponse, d rules.Decision) error {
+	sr.Translation = &TranslationResponse{Method: d.Method, Rule: d.Rule, Original: sr.Description}
+	out, err := h.translator.Translate(ctx, sr.Description, d.Method)
+	if err != nil {
+		sr.Translation.Code = errorCode(err)
+		return err
+	}
+
+	sr.Description = out
+	sr.Translation.Success = true
+	return nil
+}
+
+// errorCode retrieves the error code of a translation error.
+func errorCode(err error) errors.Code {
+	var e *errors.Error
+	if _errors.As(err, &e) {
+		return e.Code
 	}
+	return errors.CodeUnknownError
 }
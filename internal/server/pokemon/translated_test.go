@@ -1,6 +1,7 @@
 package pokemon
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,9 +13,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/jacklaaa89/pokeapi/internal/api/apitest/mock"
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
 	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
 	"github.com/jacklaaa89/pokeapi/internal/pokeapi"
 	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+	"github.com/jacklaaa89/pokeapi/internal/server/rules"
 )
 
 // these three types are copied directly from the translation/types.go
@@ -45,6 +48,8 @@ func TestTranslated(t *testing.T) {
 	tt := []struct {
 		Name     string
 		Vars     map[string]string // URL variables.
+		Query    string            // Query the query string of the request.
+		Methods  translation.Registry
 		Setup    func(m mock.API)
 		Expected func(t *testing.T, w *httptest.ResponseRecorder)
 	}{
@@ -105,6 +110,9 @@ func TestTranslated(t *testing.T) {
 				assert.Equal(t, "rare", res.Data.Habitat)
 				assert.True(t, res.Data.IsLegendary)
 				assert.Equal(t, "mewtwo", res.Data.Name)
+				assert.Equal(t, &TranslationResponse{
+					Method: translation.Yoda, Rule: "legendary", Success: true, Original: "a test description",
+				}, res.Data.Translation)
 
 			},
 		},
@@ -184,6 +192,9 @@ func TestTranslated(t *testing.T) {
 				assert.Equal(t, "rare", res.Data.Habitat)
 				assert.False(t, res.Data.IsLegendary)
 				assert.Equal(t, "mewtwo", res.Data.Name)
+				assert.Equal(t, &TranslationResponse{
+					Method: translation.Shakespeare, Rule: rules.DefaultRule, Success: true, Original: "a test description",
+				}, res.Data.Translation)
 			},
 		},
 		{
@@ -207,19 +218,138 @@ func TestTranslated(t *testing.T) {
 			},
 			Expected: func(t *testing.T, w *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, warningTranslationFailed, w.Header().Get("Warning"))
 				res := decode(t, w)
 				assert.NotNil(t, res)
 				assert.Equal(t, "a test description", res.Data.Description)
 				assert.Equal(t, "rare", res.Data.Habitat)
 				assert.False(t, res.Data.IsLegendary)
 				assert.Equal(t, "mewtwo", res.Data.Name)
+				assert.Equal(t, translation.Shakespeare, res.Data.Translation.Method)
+				assert.False(t, res.Data.Translation.Success)
+				assert.NotEmpty(t, res.Data.Translation.Code)
+				assert.Equal(t, "a test description", res.Data.Translation.Original)
+			},
+		},
+		{
+			Name:  "StrictRateLimited",
+			Vars:  map[string]string{"name": "mewtwo"},
+			Query: "?strict=true",
+			Setup: func(m mock.API) {
+				m.Expect("/pokemon-species/mewtwo", http.MethodGet).
+					WithResult(http.StatusOK, &pokeapi.Species{
+						Name:        "mewtwo",
+						IsLegendary: true,
+						Habitat:     &pokeapi.NamedAPIResource{Name: "rare"},
+						FlavorText: []*pokeapi.FlavorText{
+							{Text: "a test description", Language: &pokeapi.NamedAPIResource{Name: "en"}},
+						},
+					})
+
+				// the failed translation is returned as an error.
+				m.Expect("/yoda.json", http.MethodGet).WithStatusCode(http.StatusTooManyRequests)
+			},
+			Expected: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				assert.Empty(t, w.Header().Get("Warning"))
+
+				res := new(struct {
+					Error struct {
+						Code errors.Code `json:"code"`
+					} `json:"error"`
+				})
+				require.NoError(t, formatter.Decode(w.Body, res))
+				assert.Equal(t, errors.CodeServerUnavailable, res.Error.Code)
+			},
+		},
+		{
+			Name:  "StrictSuccess",
+			Vars:  map[string]string{"name": "mewtwo"},
+			Query: "?strict=true",
+			Setup: func(m mock.API) {
+				m.Expect("/pokemon-species/mewtwo", http.MethodGet).
+					WithResult(http.StatusOK, &pokeapi.Species{
+						Name:    "mewtwo",
+						Habitat: &pokeapi.NamedAPIResource{Name: "rare"},
+						FlavorText: []*pokeapi.FlavorText{
+							{Text: "a test description", Language: &pokeapi.NamedAPIResource{Name: "en"}},
+						},
+					})
+				m.Expect("/shakespeare.json", http.MethodGet).
+					WithResult(http.StatusOK, &response{
+						Success:  responseSuccessData{Total: 1},
+						Contents: responseContents{Translated: "a translated description"},
+					})
+			},
+			Expected: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				res := decode(t, w)
+				assert.Equal(t, "a translated description", res.Data.Description)
+				assert.True(t, res.Data.Translation.Success)
+			},
+		},
+		{
+			Name:  "MethodOverride",
+			Vars:  map[string]string{"name": "mewtwo"},
+			Query: "?method=Shakespeare",
+			Setup: func(m mock.API) {
+				m.Expect("/pokemon-species/mewtwo", http.MethodGet).
+					WithResult(http.StatusOK, &pokeapi.Species{
+						Name:        "mewtwo",
+						IsLegendary: true,
+						Habitat:     &pokeapi.NamedAPIResource{Name: "rare"},
+						FlavorText: []*pokeapi.FlavorText{
+							{Text: "a test description", Language: &pokeapi.NamedAPIResource{Name: "en"}},
+						},
+					})
+
+				// the method supplied with the request takes precedence over the rules.
+				m.Expect("/shakespeare.json", http.MethodGet).
+					WithResult(http.StatusOK, &response{
+						Success:  responseSuccessData{Total: 1},
+						Contents: responseContents{Translated: "a translated description"},
+					})
+			},
+			Expected: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				res := decode(t, w)
+				assert.Equal(t, translation.Shakespeare, res.Data.Translation.Method)
+				assert.Equal(t, ruleRequest, res.Data.Translation.Rule)
+			},
+		},
+		{
+			Name:  "InvalidMethod",
+			Vars:  map[string]string{"name": "mewtwo"},
+			Query: "?method=pirate",
+			Setup: func(m mock.API) {},
+			Expected: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name:    "UnavailableMethod",
+			Vars:    map[string]string{"name": "mewtwo"},
+			Query:   "?method=shakespeare",
+			Methods: translation.NewRegistry(translation.Yoda),
+			Setup:   func(m mock.API) {},
+			Expected: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name:  "InvalidStrict",
+			Vars:  map[string]string{"name": "mewtwo"},
+			Query: "?strict=maybe",
+			Setup: func(m mock.API) {},
+			Expected: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
 			},
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.Name, func(st *testing.T) {
-			p := setup(tc.Setup)
+			p, h := setup(tc.Setup)
 			defer p.Close()
 
 			v := tc.Vars
@@ -227,15 +357,19 @@ func TestTranslated(t *testing.T) {
 				v = make(map[string]string)
 			}
 
+			if tc.Methods != nil {
+				h.methods = tc.Methods
+			}
+
 			// set up the function to get the URL parameters for tests.
-			getVars = func(*http.Request) map[string]string {
+			h.vars = func(*http.Request) map[string]string {
 				return v
 			}
 
 			w := httptest.NewRecorder()
 			l := fmt.New(fmt.LevelNone)
 
-			req, err := http.NewRequest(http.MethodGet, "/get", nil)
+			req, err := http.NewRequest(http.MethodGet, "/get"+tc.Query, nil)
 			require.NoError(st, err)
 
 			ml := []mux.MiddlewareFunc{
@@ -243,10 +377,100 @@ func TestTranslated(t *testing.T) {
 				middleware.WithRequestID(),
 			}
 
-			withMiddleware(http.HandlerFunc(Translated), ml...).ServeHTTP(w, req)
+			withMiddleware(http.HandlerFunc(h.Translated), ml...).ServeHTTP(w, req)
 
 			tc.Expected(st, w)
 			assert.NoError(st, p.AllExpectationsMet())
 		})
 	}
 }
+
+func TestTranslated_TypeRule(t *testing.T) {
+	e, err := rules.New(rules.Config{
+		Default: "shakespeare",
+		Rules:   []rules.Rule{{Name: "psychic", When: rules.Condition{Type: []string{"psychic"}}, Method: "yoda"}},
+	})
+	require.NoError(t, err)
+
+	p, h := setup(func(m mock.API) {
+		m.Expect("/pokemon-species/mewtwo", http.MethodGet).
+			WithResult(http.StatusOK, &pokeapi.Species{
+				Name:      "mewtwo",
+				Habitat:   &pokeapi.NamedAPIResource{Name: "rare"},
+				Varieties: []*pokeapi.Variety{{IsDefault: true, Pokemon: &pokeapi.NamedAPIResource{Name: "mewtwo"}}},
+				FlavorText: []*pokeapi.FlavorText{
+					{Text: "a test description", Language: &pokeapi.NamedAPIResource{Name: "en"}},
+				},
+			})
+
+		// the types are only retrieved as a rule requires them.
+		m.Expect("/pokemon/mewtwo", http.MethodGet).
+			WithResult(http.StatusOK, &pokeapi.Pokemon{
+				Name:  "mewtwo",
+				Types: []*pokeapi.PokemonType{{Slot: 1, Type: &pokeapi.NamedAPIResource{Name: "psychic"}}},
+			})
+		m.Expect("/yoda.json", http.MethodGet).
+			WithResult(http.StatusOK, &response{
+				Success:  responseSuccessData{Total: 1},
+				Contents: responseContents{Translated: "a translated description"},
+			})
+	})
+	defer p.Close()
+
+	h.rules = e
+	h.vars = func(*http.Request) map[string]string { return map[string]string{"name": "mewtwo"} }
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/get", nil)
+	require.NoError(t, err)
+
+	withMiddleware(http.HandlerFunc(h.Translated),
+		middleware.WithLogger(fmt.New(fmt.LevelNone)), middleware.WithRequestID()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := decode(t, w)
+	assert.Equal(t, translation.Yoda, res.Data.Translation.Method)
+	assert.Equal(t, "psychic", res.Data.Translation.Rule)
+	assert.NoError(t, p.AllExpectationsMet())
+}
+
+func TestTranslated_Translator(t *testing.T) {
+	p, h := setup(func(m mock.API) {
+		m.Expect("/pokemon-species/zubat", http.MethodGet).
+			WithResult(http.StatusOK, &pokeapi.Species{
+				Name:    "zubat",
+				Habitat: &pokeapi.NamedAPIResource{Name: "cave"},
+				FlavorText: []*pokeapi.FlavorText{
+					{Text: "a test description", Language: &pokeapi.NamedAPIResource{Name: "en"}},
+				},
+			})
+	})
+	defer p.Close()
+
+	// the first translator does not support the method so the second is used.
+	var methods []translation.Method
+	h.translator = translation.Composite(
+		translation.TranslatorFunc(func(_ context.Context, _ string, m translation.Method) (string, error) {
+			return "", translation.ErrUnsupportedMethod
+		}),
+		translation.TranslatorFunc(func(_ context.Context, in string, m translation.Method) (string, error) {
+			methods = append(methods, m)
+			return m.Name() + ": " + in, nil
+		}),
+	)
+	h.vars = func(*http.Request) map[string]string { return map[string]string{"name": "zubat"} }
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/get", nil)
+	require.NoError(t, err)
+
+	withMiddleware(http.HandlerFunc(h.Translated),
+		middleware.WithLogger(fmt.New(fmt.LevelNone)), middleware.WithRequestID()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := decode(t, w)
+	assert.Equal(t, "yoda: a test description", res.Data.Description)
+	assert.True(t, res.Data.Translation.Success)
+	assert.Equal(t, []translation.Method{translation.Yoda}, methods)
+	assert.NoError(t, p.AllExpectationsMet())
+}
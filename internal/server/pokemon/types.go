@@ -1,34 +1,55 @@
 package pokemon
 
 import (
+	"context"
 	"net/http"
-	"os"
 
 	"github.com/gorilla/mux"
 
 	"github.com/jacklaaa89/pokeapi/internal/api"
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
 	"github.com/jacklaaa89/pokeapi/internal/pokeapi"
+	"github.com/jacklaaa89/pokeapi/internal/server/rules"
 	"github.com/jacklaaa89/pokeapi/internal/translation"
 )
 
 // varFunc a function which is used to retrieve URL parameters from a request.
 type varFunc func(*http.Request) map[string]string
 
-// getVars the function to use to get the URL parameters.
-// its impossible to set-up mux for tests as it uses an internal
-// const for the context key, so we can override the function here.
-var getVars varFunc = mux.Vars
+// SpeciesSource retrieves the species and pokemon, i.e the PokeAPI pokemon service.
+type SpeciesSource interface {
+	// Species retrieves the species of the pokemon by name or id.
+	Species(ctx context.Context, reference string) (*pokeapi.Species, error)
+	// Pokemon retrieves the pokemon by name or id.
+	Pokemon(ctx context.Context, reference string) (*pokeapi.Pokemon, error)
+}
 
-// cfgTranslationAPIKey the environment variable key to
-// use to set the translation API key. defaults to an empty string
-// if not defined.
-const cfgTranslationAPIKey = "TRANSLATION_API_KEY"
+// Handler handles the pokemon resource endpoints using the dependencies it is initialised with.
+type Handler struct {
+	species    SpeciesSource
+	translator translation.Translator
+	rules      *rules.Engine
+	methods    translation.Registry
 
-// initialisations of the API clients to use.
-var (
-	pokemonAPI     = pokeapi.New()
-	translationAPI = translation.New(os.Getenv(cfgTranslationAPIKey))
-)
+	// vars the function to use to get the URL parameters.
+	// its impossible to set-up mux for tests as it uses an internal
+	// const for the context key, so we can override the function here.
+	vars varFunc
+}
+
+// NewHandler initialises a handler which retrieves species from the species source and
+// translates descriptions using the translator, the method is chosen using the rules
+// or the default rules if nil. The methods which can be supplied with ?method= are
+// retrieved from the registry, or every registered method if nil.
+func NewHandler(species SpeciesSource, t translation.Translator, r *rules.Engine, methods translation.Registry) *Handler {
+	if r == nil {
+		r = rules.Default()
+	}
+	if methods == nil {
+		methods = translation.Registered()
+	}
+	return &Handler{species: species, translator: t, rules: r, methods: methods, vars: mux.Vars}
+}
 
 // SpeciesResponse the response from the /pokemon/{name} and
 // /pokemon/{name}/translated
@@ -42,6 +63,25 @@ type SpeciesResponse struct {
 	Habitat string `json:"habitat"`
 	// IsLegendary determines if the pokemon is classed as a legendary pokemon.
 	IsLegendary bool `json:"is_legendary"`
+	// Translation describes the translation applied to the description, this is
+	// only returned from /pokemon/{name}/translated.
+	Translation *TranslationResponse `json:"translation,omitempty"`
+}
+
+// TranslationResponse describes the translation applied to the description.
+type TranslationResponse struct {
+	// Method the translation method chosen for the pokemon.
+	Method translation.Method `json:"method"`
+	// Rule the name of the translation rule which chose the method, default when
+	// no rule matched or request when the method was supplied with the request.
+	Rule string `json:"rule"`
+	// Success whether the description was translated, the description is
+	// untranslated if the translation failed.
+	Success bool `json:"success"`
+	// Code the error code the translation failed with, if any, i.e rate_limit_exceeded.
+	Code errors.Code `json:"code,omitempty"`
+	// Original the untranslated description.
+	Original string `json:"original"`
 }
 
 // fromSpecies takes the result from the poke-api and converts it into a structure
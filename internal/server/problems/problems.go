@@ -0,0 +1,34 @@
+// Package problems serves the catalogue of RFC 7807 problem types returned from the server
+// so that the type URI of each problem resolves to its documentation.
+package problems
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/server/helpers"
+)
+
+// List http.HandlerFunc which handles /problems
+// responds with every problem type in the catalogue.
+func List(w http.ResponseWriter, req *http.Request) {
+	helpers.RespondOK(req.Context(), w, helpers.ProblemTypes())
+}
+
+// Get http.HandlerFunc which handles /problems/{code}
+// responds with the problem type for the code.
+func Get(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	code := errors.Code(mux.Vars(req)["code"])
+
+	pt, ok := helpers.LookupProblemType(code)
+	if !ok {
+		helpers.RespondError(ctx, w, helpers.NotFound(fmt.Errorf("unknown problem type: %s", code)))
+		return
+	}
+
+	helpers.RespondOK(ctx, w, pt)
+}
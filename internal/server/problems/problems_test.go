@@ -0,0 +1,68 @@
+package problems
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/server/helpers"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+)
+
+func TestProblems(t *testing.T) {
+	m := mux.NewRouter()
+	m.Use(middleware.WithRequestID(), middleware.WithLogger(fmt.New(fmt.LevelNone)))
+	m.HandleFunc("/problems", List)
+	m.HandleFunc("/problems/{code}", Get)
+
+	tt := []struct {
+		Name     string
+		Path     string
+		Expected func(t *testing.T, code int, body []byte)
+	}{
+		{
+			Name: "List",
+			Path: "/problems",
+			Expected: func(t *testing.T, code int, body []byte) {
+				var res struct{ Data []helpers.ProblemType }
+				require.NoError(t, json.Unmarshal(body, &res))
+				assert.Equal(t, http.StatusOK, code)
+				assert.Equal(t, helpers.ProblemTypes(), res.Data)
+			},
+		},
+		{
+			Name: "Get",
+			Path: "/problems/not_found",
+			Expected: func(t *testing.T, code int, body []byte) {
+				var res struct{ Data helpers.ProblemType }
+				require.NoError(t, json.Unmarshal(body, &res))
+				assert.Equal(t, http.StatusOK, code)
+				assert.Equal(t, errors.CodeNotFound, res.Data.Code)
+				assert.Equal(t, "/problems/not_found", res.Data.Type)
+				assert.Equal(t, http.StatusNotFound, res.Data.Status)
+			},
+		},
+		{
+			Name: "Unknown",
+			Path: "/problems/unknown",
+			Expected: func(t *testing.T, code int, _ []byte) {
+				assert.Equal(t, http.StatusNotFound, code)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			r := httptest.NewRecorder()
+			m.ServeHTTP(r, httptest.NewRequest(http.MethodGet, tc.Path, nil))
+			tc.Expected(st, r.Code, r.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// Package rules provides a rules engine which chooses the translation method to
+// apply to the description of a species from its attributes.
+//
+// rules are evaluated in the order they are defined, the method of the first rule
+// whose conditions all match the species is chosen, otherwise the default method is used.
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// DefaultRule the name reported when no rule matches and the default method is chosen.
+const DefaultRule = "default"
+
+// Species the attributes of a species which rules are evaluated against.
+type Species struct {
+	Habitat    string   // Habitat the habitat the species can be encountered in, i.e cave.
+	Legendary  bool     // Legendary whether the species is legendary.
+	Mythical   bool     // Mythical whether the species is mythical.
+	Color      string   // Color the color of the species, i.e blue.
+	Generation string   // Generation the generation the species was introduced in, i.e generation-i.
+	Types      []string // Types the types of the default variety of the species, i.e psychic.
+}
+
+// Condition the conditions a species must match for a rule to apply, every
+// defined condition must match. A list matches if any of its values match.
+type Condition struct {
+	Habitat    []string `json:"habitat,omitempty" yaml:"habitat,omitempty"`
+	Legendary  *bool    `json:"legendary,omitempty" yaml:"legendary,omitempty"`
+	Mythical   *bool    `json:"mythical,omitempty" yaml:"mythical,omitempty"`
+	Color      []string `json:"color,omitempty" yaml:"color,omitempty"`
+	Generation []string `json:"generation,omitempty" yaml:"generation,omitempty"`
+	Type       []string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// empty determines whether no conditions are defined.
+func (c Condition) empty() bool {
+	return len(c.Habitat) == 0 && c.Legendary == nil && c.Mythical == nil &&
+		len(c.Color) == 0 && len(c.Generation) == 0 && len(c.Type) == 0
+}
+
+// match determines whether the species matches every defined condition.
+func (c Condition) match(s Species) bool {
+	return anyOf(c.Habitat, s.Habitat) &&
+		(c.Legendary == nil || *c.Legendary == s.Legendary) &&
+		(c.Mythical == nil || *c.Mythical == s.Mythical) &&
+		anyOf(c.Color, s.Color) &&
+		anyOf(c.Generation, s.Generation) &&
+		anyOf(c.Type, s.Types...)
+}
+
+// anyOf determines whether any of the values is in the allowed list, an empty
+// allowed list matches any value.
+func anyOf(allowed []string, values ...string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, a := range allowed {
+		for _, v := range values {
+			if strings.EqualFold(a, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Rule a rule which chooses Method when a species matches When.
+type Rule struct {
+	Name   string    `json:"name" yaml:"name"`
+	When   Condition `json:"when" yaml:"when"`
+	Method string    `json:"method" yaml:"method"`
+}
+
+// Config the configuration of the rules engine.
+type Config struct {
+	Default string `json:"default" yaml:"default"` // Default the method used when no rule matches.
+	Rules   []Rule `json:"rules" yaml:"rules"`     // Rules the ordered rules.
+}
+
+// Decision the translation method chosen for a species.
+type Decision struct {
+	Method translation.Method // Method the chosen translation method.
+	Rule   string             // Rule the name of the matched rule, or DefaultRule.
+}
+
+// rule a validated rule.
+type rule struct {
+	name   string
+	when   Condition
+	method translation.Method
+}
+
+// Engine chooses the translation method for a species.
+type Engine struct {
+	rules []rule
+	def   translation.Method
+}
+
+// New validates the configuration and initialises a new rules engine.
+func New(c Config) (*Engine, error) {
+	def, err := translation.ParseMethod(c.Default)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default: %w", err)
+	}
+
+	e := &Engine{rules: make([]rule, 0, len(c.Rules)), def: def}
+	names := make(map[string]bool, len(c.Rules))
+	for i, r := range c.Rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("rule %s: duplicate name", r.Name)
+		}
+		names[r.Name] = true
+
+		if r.When.empty() {
+			return nil, fmt.Errorf("rule %s: at least one condition is required", r.Name)
+		}
+
+		m, err := translation.ParseMethod(r.Method)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		e.rules = append(e.rules, rule{name: r.Name, when: r.When, method: m})
+	}
+	return e, nil
+}
+
+// Parse parses a JSON or YAML configuration, chosen by format which is either json
+// or yaml, and initialises a new rules engine. Unknown fields are rejected.
+func Parse(b []byte, format string) (*Engine, error) {
+	var c Config
+	switch format {
+	case "json":
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.DisallowUnknownFields()
+		if err := d.Decode(&c); err != nil {
+			return nil, err
+		}
+	case "yaml", "yml":
+		d := yaml.NewDecoder(bytes.NewReader(b))
+		d.KnownFields(true)
+		if err := d.Decode(&c); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unsupported rules format: " + format)
+	}
+	return New(c)
+}
+
+// Load loads the configuration from the file at path, the format is determined
+// by the file extension, and initialises a new rules engine.
+func Load(path string) (*Engine, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := Parse(b, strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return e, nil
+}
+
+// Default initialises the default rules engine, which applies the yoda translation
+// to species found in caves or which are legendary and shakespeare to any other.
+func Default() *Engine {
+	legendary := true
+	e, err := New(Config{
+		Default: translation.Shakespeare.Name(),
+		Rules: []Rule{
+			{Name: "cave", When: Condition{Habitat: []string{"cave"}}, Method: translation.Yoda.Name()},
+			{Name: "legendary", When: Condition{Legendary: &legendary}, Method: translation.Yoda.Name()},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Choose chooses the translation method for the species.
+func (e *Engine) Choose(s Species) Decision {
+	for _, r := range e.rules {
+		if r.when.match(s) {
+			return Decision{Method: r.method, Rule: r.name}
+		}
+	}
+	return Decision{Method: e.def, Rule: DefaultRule}
+}
+
+// NeedsTypes determines whether any rule has a condition on the types of a species, the
+// types are retrieved using a separate request so are only retrieved when required.
+func (e *Engine) NeedsTypes() bool {
+	for _, r := range e.rules {
+		if len(r.when.Type) > 0 {
+			return true
+		}
+	}
+	return false
+}
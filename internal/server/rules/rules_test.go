@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+func TestDefault(t *testing.T) {
+	e := Default()
+	assert.False(t, e.NeedsTypes())
+
+	tt := []struct {
+		Name     string
+		Species  Species
+		Expected Decision
+	}{
+		{Name: "Cave", Species: Species{Habitat: "cave"}, Expected: Decision{translation.Yoda, "cave"}},
+		{Name: "Legendary", Species: Species{Habitat: "rare", Legendary: true}, Expected: Decision{translation.Yoda, "legendary"}},
+		{Name: "Default", Species: Species{Habitat: "rare"}, Expected: Decision{translation.Shakespeare, DefaultRule}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			assert.Equal(st, tc.Expected, e.Choose(tc.Species))
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("YAML", func(st *testing.T) {
+		e, err := Load(filepath.Join("testdata", "rules.yaml"))
+		require.NoError(st, err)
+		assert.True(st, e.NeedsTypes())
+
+		// every condition of a rule must match.
+		assert.Equal(st, Decision{translation.Shakespeare, DefaultRule}, e.Choose(Species{Legendary: true, Types: []string{"fire"}}))
+		assert.Equal(st, Decision{translation.Yoda, "legendary-psychic"}, e.Choose(Species{Legendary: true, Types: []string{"Psychic"}}))
+		assert.Equal(st, Decision{translation.Yoda, "mythical"}, e.Choose(Species{Mythical: true}))
+
+		// rules are evaluated in order.
+		assert.Equal(st, Decision{translation.Yoda, "cave"}, e.Choose(Species{Habitat: "cave", Mythical: true}))
+	})
+
+	t.Run("JSON", func(st *testing.T) {
+		e, err := Load(filepath.Join("testdata", "rules.json"))
+		require.NoError(st, err)
+		assert.False(st, e.NeedsTypes())
+		assert.Equal(st, Decision{translation.Shakespeare, "blue"}, e.Choose(Species{Color: "blue", Generation: "generation-i"}))
+		assert.Equal(st, Decision{translation.Yoda, DefaultRule}, e.Choose(Species{Color: "blue", Generation: "generation-ii"}))
+	})
+
+	t.Run("Missing", func(st *testing.T) {
+		_, err := Load(filepath.Join("testdata", "missing.yaml"))
+		assert.Error(st, err)
+	})
+
+	t.Run("UnsupportedFormat", func(st *testing.T) {
+		f, err := ioutil.TempFile("", "rules-*.ini")
+		require.NoError(st, err)
+		defer os.Remove(f.Name())
+
+		_, err = Load(f.Name())
+		assert.Error(st, err)
+	})
+}
+
+func TestParse(t *testing.T) {
+	tt := []struct {
+		Name   string
+		Format string
+		Config string
+		Error  string
+	}{
+		{Name: "UnknownField", Format: "yaml", Config: "default: yoda\nrulez: []", Error: "field rulez not found"},
+		{Name: "UnknownJSONField", Format: "json", Config: `{"default": "yoda", "rulez": []}`, Error: "unknown field"},
+		{Name: "UnknownDefault", Format: "yaml", Config: "default: pirate", Error: "invalid default: unknown translation method: pirate"},
+		{Name: "MissingDefault", Format: "yaml", Config: "rules: []", Error: "invalid default"},
+		{
+			Name:   "UnknownMethod",
+			Format: "yaml",
+			Config: "default: yoda\nrules:\n  - name: cave\n    when: {habitat: [cave]}\n    method: pirate",
+			Error:  "rule cave: unknown translation method: pirate",
+		},
+		{
+			Name:   "MissingName",
+			Format: "yaml",
+			Config: "default: yoda\nrules:\n  - when: {habitat: [cave]}\n    method: yoda",
+			Error:  "rule 1: name is required",
+		},
+		{
+			Name:   "DuplicateName",
+			Format: "yaml",
+			Config: "default: yoda\nrules:\n" +
+				"  - {name: cave, when: {habitat: [cave]}, method: yoda}\n" +
+				"  - {name: cave, when: {habitat: [sea]}, method: yoda}",
+			Error: "rule cave: duplicate name",
+		},
+		{
+			Name:   "NoConditions",
+			Format: "yaml",
+			Config: "default: yoda\nrules:\n  - {name: all, when: {}, method: yoda}",
+			Error:  "rule all: at least one condition is required",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			_, err := Parse([]byte(tc.Config), tc.Format)
+			require.Error(st, err)
+			assert.Contains(st, err.Error(), tc.Error)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package status
+
+import (
+	"net/http"
+
+	"github.com/jacklaaa89/pokeapi/internal/server/helpers"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// TranslationResponse the response from /status/translation.
+type TranslationResponse struct {
+	// Quota the state of the fun-translations API quota, translations are skipped
+	// while the quota is exhausted.
+	Quota translation.Quota `json:"quota"`
+}
+
+// Translation generates a http.HandlerFunc which handles /status/translation
+// responds with the state of the translation API quota retrieved using quota,
+// i.e to see why translations stopped.
+func Translation(quota func() translation.Quota) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		helpers.RespondOK(req.Context(), w, &TranslationResponse{Quota: quota()})
+	}
+}
@@ -0,0 +1,38 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+func TestTranslation(t *testing.T) {
+	reset := time.Date(2021, 6, 1, 13, 0, 0, 0, time.UTC)
+	var h http.Handler = Translation(func() translation.Quota {
+		return translation.Quota{Limit: 5, Remaining: 0, Total: 12, Exhausted: true, Reset: &reset}
+	})
+	h = middleware.WithLogger(fmt.New(fmt.LevelNone)).Middleware(h)
+	h = middleware.WithRequestID().Middleware(h)
+
+	r := httptest.NewRecorder()
+	h.ServeHTTP(r, httptest.NewRequest(http.MethodGet, "/status/translation", nil))
+	assert.Equal(t, http.StatusOK, r.Code)
+
+	var res struct {
+		Data TranslationResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &res))
+	assert.Equal(t, 5, res.Data.Quota.Limit)
+	assert.Equal(t, int64(12), res.Data.Quota.Total)
+	assert.True(t, res.Data.Quota.Exhausted)
+	assert.Equal(t, reset, res.Data.Quota.Reset.UTC())
+}
@@ -0,0 +1,29 @@
+// Package translations serves the translation methods which are available to the
+// /pokemon/{name}/translated endpoint, i.e using ?method=.
+package translations
+
+import (
+	"net/http"
+
+	"github.com/jacklaaa89/pokeapi/internal/server/helpers"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// MethodResponse describes an available translation method.
+type MethodResponse struct {
+	Name translation.Method `json:"name"` // Name the name of the translation method.
+}
+
+// List generates a http.HandlerFunc which handles /translations
+// responds with every translation method available from the registry sorted by name.
+func List(r translation.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		methods := r.Methods()
+		res := make([]MethodResponse, 0, len(methods))
+		for _, m := range methods {
+			res = append(res, MethodResponse{Name: m})
+		}
+
+		helpers.RespondOK(req.Context(), w, res)
+	}
+}
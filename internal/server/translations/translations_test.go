@@ -0,0 +1,61 @@
+package translations
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/server/middleware"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+func TestList(t *testing.T) {
+	require.NoError(t, translation.RegisterAll("pirate"))
+
+	tt := []struct {
+		Name     string
+		Registry translation.Registry
+		Expected []string
+	}{
+		{
+			Name:     "Registered",
+			Registry: translation.Registered(),
+			Expected: []string{"pirate", "shakespeare", "yoda"},
+		},
+		{
+			Name:     "Subset",
+			Registry: translation.NewRegistry(translation.Yoda),
+			Expected: []string{"yoda"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			var h http.Handler = List(tc.Registry)
+			h = middleware.WithLogger(fmt.New(fmt.LevelNone)).Middleware(h)
+			h = middleware.WithRequestID().Middleware(h)
+
+			r := httptest.NewRecorder()
+			h.ServeHTTP(r, httptest.NewRequest(http.MethodGet, "/translations", nil))
+			assert.Equal(st, http.StatusOK, r.Code)
+
+			var res struct {
+				Data []struct {
+					Name string `json:"name"`
+				} `json:"data"`
+			}
+			require.NoError(st, json.Unmarshal(r.Body.Bytes(), &res))
+
+			names := make([]string, 0, len(res.Data))
+			for _, m := range res.Data {
+				names = append(names, m.Name)
+			}
+			assert.Equal(st, tc.Expected, names)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package translation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+)
+
+// defaultConcurrency the default amount of translations performed concurrently in a batch.
+const defaultConcurrency = 4
+
+// Item a text to translate using a translation method.
+type Item struct {
+	Text   string // Text the text to translate.
+	Method Method // Method the translation method to use.
+}
+
+// Result the result of translating an item in a batch.
+type Result struct {
+	Item
+	Output string // Output the translated text, empty if the translation failed.
+	Err    error  // Err the error the translation failed with, if any.
+}
+
+// Cache a store of previous translations consulted before an item is translated, i.e
+// the translation memory.
+type Cache interface {
+	// Get retrieves the translation of the text using method, false is returned if the
+	// text has not been translated.
+	Get(text string, method Method) (string, bool)
+}
+
+// Batch translates many items, each distinct item is only translated once and items in
+// the cache are not translated at all.
+type Batch struct {
+	// Translator the translator used to translate each item.
+	Translator Translator
+	// Cache the cache consulted before an item is translated, this is optional.
+	Cache Cache
+	// Concurrency the maximum amount of items translated concurrently, defaults to 4.
+	Concurrency int
+}
+
+// Translate translates each of the items, a result is returned for each item in the same order.
+//
+// once a translation fails as the rate limit is exceeded the remaining items are not translated
+// and fail with the same error, as do any items remaining once the context is cancelled.
+func (b Batch) Translate(ctx context.Context, items []Item) []Result {
+	results := make([]Result, len(items))
+
+	// the indexes of the results for each distinct item, in the order they were first seen.
+	pending := make(map[Item][]int)
+	distinct := make([]Item, 0, len(items))
+	for i, it := range items {
+		results[i].Item = it
+		if b.Cache != nil {
+			if out, ok := b.Cache.Get(it.Text, it.Method); ok {
+				results[i].Output = out
+				continue
+			}
+		}
+
+		if _, ok := pending[it]; !ok {
+			distinct = append(distinct, it)
+		}
+		pending[it] = append(pending[it], i)
+	}
+
+	n := b.Concurrency
+	if n <= 0 {
+		n = defaultConcurrency
+	}
+	if n > len(distinct) {
+		n = len(distinct)
+	}
+
+	var (
+		mu      sync.Mutex
+		limited error
+		wg      sync.WaitGroup
+	)
+
+	jobs := make(chan Item)
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for it := range jobs {
+				mu.Lock()
+				err := limited
+				mu.Unlock()
+
+				var out string
+				switch {
+				case err != nil:
+				case ctx.Err() != nil:
+					err = ctx.Err()
+				default:
+					out, err = b.Translator.Translate(ctx, it.Text, it.Method)
+				}
+
+				if errors.IsRateLimited(err) {
+					mu.Lock()
+					limited = err
+					mu.Unlock()
+				}
+
+				// each distinct item is only handled by a single worker.
+				for _, i := range pending[it] {
+					results[i].Output, results[i].Err = out, err
+				}
+			}
+		}()
+	}
+
+	for _, it := range distinct {
+		jobs <- it
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
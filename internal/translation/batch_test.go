@@ -0,0 +1,186 @@
+package translation
+
+import (
+	"context"
+	_errors "errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+)
+
+// cache a Cache implementation backed by a map.
+type cache map[Item]string
+
+// Get implements Cache interface.
+func (c cache) Get(text string, method Method) (string, bool) {
+	out, ok := c[Item{Text: text, Method: method}]
+	return out, ok
+}
+
+// counter a translator which records the calls made against it and the maximum
+// amount of concurrent calls.
+type counter struct {
+	mu       sync.Mutex
+	calls    []Item
+	inFlight int
+	max      int
+	fn       func(ctx context.Context, it Item) (string, error)
+}
+
+// Translate implements Translator interface.
+func (c *counter) Translate(ctx context.Context, input string, method Method) (string, error) {
+	it := Item{Text: input, Method: method}
+	c.mu.Lock()
+	c.calls = append(c.calls, it)
+	c.inFlight++
+	if c.inFlight > c.max {
+		c.max = c.inFlight
+	}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	if c.fn != nil {
+		return c.fn(ctx, it)
+	}
+	return method.Name() + ": " + input, nil
+}
+
+func TestBatch_Translate(t *testing.T) {
+	errFailed := _errors.New("failed")
+	errLimited := &errors.Error{Code: errors.CodeRateLimitExceeded}
+
+	tt := []struct {
+		Name     string
+		Batch    func(c *counter) Batch
+		Context  func() context.Context
+		Items    []Item
+		Expected func(t *testing.T, c *counter, results []Result)
+	}{
+		{
+			Name:  "Empty",
+			Batch: func(c *counter) Batch { return Batch{Translator: c} },
+			Expected: func(t *testing.T, c *counter, results []Result) {
+				assert.Empty(t, results)
+				assert.Empty(t, c.calls)
+			},
+		},
+		{
+			Name:  "Deduplicated",
+			Batch: func(c *counter) Batch { return Batch{Translator: c} },
+			Items: []Item{{"a", Yoda}, {"a", Shakespeare}, {"a", Yoda}, {"b", Yoda}},
+			Expected: func(t *testing.T, c *counter, results []Result) {
+				require.Len(t, results, 4)
+				assert.Equal(t, "yoda: a", results[0].Output)
+				assert.Equal(t, "shakespeare: a", results[1].Output)
+				assert.Equal(t, "yoda: a", results[2].Output)
+				assert.Equal(t, Item{"b", Yoda}, results[3].Item)
+				assert.Equal(t, "yoda: b", results[3].Output)
+				assert.Len(t, c.calls, 3)
+			},
+		},
+		{
+			Name: "Cached",
+			Batch: func(c *counter) Batch {
+				return Batch{Translator: c, Cache: cache{{"a", Yoda}: "cached"}}
+			},
+			Items: []Item{{"a", Yoda}, {"b", Yoda}},
+			Expected: func(t *testing.T, c *counter, results []Result) {
+				assert.Equal(t, "cached", results[0].Output)
+				assert.Equal(t, "yoda: b", results[1].Output)
+				assert.Equal(t, []Item{{"b", Yoda}}, c.calls)
+			},
+		},
+		{
+			Name: "PerItemErrors",
+			Batch: func(c *counter) Batch {
+				c.fn = func(_ context.Context, it Item) (string, error) {
+					if it.Text == "b" {
+						return "", errFailed
+					}
+					return "ok", nil
+				}
+				return Batch{Translator: c}
+			},
+			Items: []Item{{"a", Yoda}, {"b", Yoda}, {"c", Yoda}},
+			Expected: func(t *testing.T, c *counter, results []Result) {
+				assert.NoError(t, results[0].Err)
+				assert.Equal(t, errFailed, results[1].Err)
+				assert.Empty(t, results[1].Output)
+				assert.NoError(t, results[2].Err)
+			},
+		},
+		{
+			Name: "RateLimited",
+			Batch: func(c *counter) Batch {
+				c.fn = func(context.Context, Item) (string, error) { return "", errLimited }
+				return Batch{Translator: c, Concurrency: 1}
+			},
+			Items: []Item{{"a", Yoda}, {"b", Yoda}, {"c", Yoda}},
+			Expected: func(t *testing.T, c *counter, results []Result) {
+				assert.Len(t, c.calls, 1)
+				for _, r := range results {
+					assert.True(t, errors.IsRateLimited(r.Err))
+				}
+			},
+		},
+		{
+			Name:  "Cancelled",
+			Batch: func(c *counter) Batch { return Batch{Translator: c} },
+			Context: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			Items: []Item{{"a", Yoda}, {"b", Yoda}},
+			Expected: func(t *testing.T, c *counter, results []Result) {
+				assert.Empty(t, c.calls)
+				for _, r := range results {
+					assert.Equal(t, context.Canceled, r.Err)
+				}
+			},
+		},
+		{
+			Name: "BoundedConcurrency",
+			Batch: func(c *counter) Batch {
+				// each translation waits until the maximum amount of translations are in flight.
+				var wg sync.WaitGroup
+				wg.Add(2)
+				c.fn = func(_ context.Context, it Item) (string, error) {
+					if it.Text == "a" || it.Text == "b" {
+						wg.Done()
+						wg.Wait()
+					}
+					return it.Text, nil
+				}
+				return Batch{Translator: c, Concurrency: 2}
+			},
+			Items: []Item{{"a", Yoda}, {"b", Yoda}, {"c", Yoda}, {"d", Yoda}, {"e", Yoda}},
+			Expected: func(t *testing.T, c *counter, results []Result) {
+				assert.Equal(t, 2, c.max)
+				assert.Len(t, c.calls, 5)
+				assert.Equal(t, "e", results[4].Output)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			ctx := context.Background()
+			if tc.Context != nil {
+				ctx = tc.Context()
+			}
+
+			c := new(counter)
+			tc.Expected(st, c, tc.Batch(c).Translate(ctx, tc.Items))
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jacklaaa89/pokeapi/internal/api/auth"
 	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
 	"github.com/jacklaaa89/pokeapi/internal/api/opts"
+	"github.com/jacklaaa89/pokeapi/internal/api/redact"
 )
 
 const (
@@ -18,7 +19,10 @@ const (
 )
 
 // Client the translations client.
-type Client struct{ c api.Client }
+type Client struct {
+	c api.Client
+	q *quota
+}
 
 // Translate takes the input and performs the translation based on the method supplied.
 func (c *Client) Translate(ctx context.Context, input string, method Method) (output string, err error) {
@@ -32,6 +36,11 @@ func (c *Client) Translate(ctx context.Context, input string, method Method) (ou
 	return r.Contents.Translated, err
 }
 
+// Credentials generates credentials which authenticate using the API secret retrieved from the
+// provider on each request, allowing the secret to be rotated. These can be supplied using
+// opts.WithCredentials along with an empty token.
+func Credentials(p auth.Provider) auth.Credentials { return auth.FromHeaderProvider(authHeader, p) }
+
 // New initialises a new client using the default URL.
 // token can be supplied as an empty string to use no authentication, this will restrict usage to
 // the free plan.
@@ -39,13 +48,30 @@ func New(token string, o ...opts.APIOption) *Client { return NewWithEndpoint(def
 
 // NewWithEndpoint initialises a new client using the supplied URL.
 // token can be supplied as an empty string to use no authentication, this will restrict usage to
-// the free plan.
+// the free plan, or to authenticate using credentials supplied using opts.WithCredentials.
+//
+// multiple endpoints to fail over between can be defined by supplying opts.WithEndpoints
+// which takes precedence over endpoint.
 func NewWithEndpoint(endpoint, token string, o ...opts.APIOption) *Client {
 	o = append(o,
 		opts.WithEncoder(json.New()),
 		opts.WithUserAgent(userAgent),
-		opts.WithCredentials(auth.FromHeader(authHeader, token)),
 	)
-	c := &Client{c: api.New(endpoint, o...)}
+
+	// an empty token keeps any credentials supplied using opts.WithCredentials, i.e OAuth2.
+	if token != "" {
+		o = append(o, opts.WithCredentials(auth.FromHeader(authHeader, token)))
+	}
+
+	// the quota is tracked from every response and calls are skipped once it is exhausted, its
+	// warnings are redacted in the same way as the log output of the client.
+	cfg := opts.Apply(o...)
+	q := &quota{log: redact.Logger(cfg.Logger, cfg.Redactor)}
+	o = append(o, opts.WithInterceptors(q))
+
+	c := &Client{c: api.New(endpoint, o...), q: q}
 	return c
 }
+
+// Quota retrieves the state of the translation API quota.
+func (c *Client) Quota() Quota { return c.q.state() }
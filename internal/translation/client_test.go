@@ -3,12 +3,16 @@ package translation
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jacklaaa89/pokeapi/internal/api/apitest/mock"
+	"github.com/jacklaaa89/pokeapi/internal/api/endpoint"
 	"github.com/jacklaaa89/pokeapi/internal/api/format/json"
+	"github.com/jacklaaa89/pokeapi/internal/api/opts"
 )
 
 func TestNew(t *testing.T) {
@@ -41,7 +45,7 @@ func TestClient_Translate(t *testing.T) {
 					Contents: responseContents{
 						Translated:  "Lost a planet, master obiwan has.",
 						Text:        "Master Obiwan has lost a planet.",
-						Translation: Yoda,
+						Translation: Yoda.Name(),
 					},
 				})
 			},
@@ -62,7 +66,7 @@ func TestClient_Translate(t *testing.T) {
 					Contents: responseContents{
 						Translated:  "Thee did giveth mr. Tim a hearty meal, but unfortunately what he did doth englut did maketh him kicketh the bucket.",
 						Text:        "You gave Mr. Tim a hearty meal, but unfortunately what he ate made him die.",
-						Translation: Shakespeare,
+						Translation: Shakespeare.Name(),
 					},
 				})
 			},
@@ -108,3 +112,25 @@ func TestClient_Translate(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWithEndpoint_Failover(t *testing.T) {
+	// the primary is never started, so requests to it fail with a connection error.
+	primary := httptest.NewUnstartedServer(http.NotFoundHandler())
+	primary.Listener.Close()
+
+	secondary := mock.NewMockAPI(json.New())
+	secondary.Expect("/yoda.json", http.MethodGet).WithResult(http.StatusOK, &response{
+		Contents: responseContents{Translated: "Lost a planet, master obiwan has."},
+	})
+	secondary.Start()
+	defer secondary.Close()
+
+	p, err := endpoint.Failover("http://"+primary.Listener.Addr().String(), secondary.URL())
+	require.NoError(t, err)
+
+	c := NewWithEndpoint(defaultURL, "", opts.WithEndpoints(p))
+	out, err := c.Translate(context.Background(), "Master Obiwan has lost a planet.", Yoda)
+	assert.NoError(t, err)
+	assert.Equal(t, "Lost a planet, master obiwan has.", out)
+	assert.NoError(t, secondary.AllExpectationsMet())
+}
@@ -0,0 +1,265 @@
+// Package memory provides a persistent translation memory, translations are deterministic for
+// a given text and method so once a text is translated the translation API is never called again.
+//
+// the memory is stored as an append-only log of JSON encoded entries, one per line, which is
+// loaded into memory when opened. Entries are keyed by a hash of the normalised text and the
+// name of the translation method.
+package memory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// Entry a single translation held in the memory.
+type Entry struct {
+	// Text the untranslated text.
+	Text string `json:"text"`
+	// Method the name of the translation method, the name is stored rather than the
+	// method as methods registered at runtime are not numbered consistently.
+	Method string `json:"method"`
+	// Translation the translated text.
+	Translation string `json:"translation"`
+}
+
+// key the key of the entry in the memory.
+func (e Entry) key() string { return Key(e.Text, e.Method) }
+
+// Key generates the key for the text and method name, the text is normalised so that
+// differences in whitespace do not generate a different key.
+func Key(text, method string) string {
+	h := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(h[:]) + "/" + strings.ToLower(method)
+}
+
+// Store a file-backed translation memory.
+type Store struct {
+	mu      sync.RWMutex
+	f       *os.File
+	entries map[string]Entry
+}
+
+// Open opens the translation memory at path, creating the file if it does not exist.
+//
+// the file is validated and loaded before it is opened for appending, an incomplete final
+// entry, i.e from a crash while appending, is truncated so it is overwritten by the next
+// entry. Entries are appended using O_APPEND, so that each entry is written to the end of
+// the file even when another process has appended to it since it was opened.
+func Open(path string) (*Store, error) {
+	s := &Store{entries: make(map[string]Entry)}
+	if err := s.load(path); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s.f = f
+	return s, nil
+}
+
+// load loads the entries from the file at path, creating the file if it does not exist,
+// and truncates any incomplete final entry.
+func (s *Store) load(path string) error {
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	size, err := s.read(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() > size {
+		return os.Truncate(path, size)
+	}
+	return nil
+}
+
+// read reads the entries from r, the size of the valid entries is returned.
+func (s *Store) read(r io.Reader) (int64, error) {
+	br := bufio.NewReader(r)
+
+	var size int64
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			// the final entry is incomplete if it is not terminated.
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		size += int64(len(b))
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			return 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		s.entries[e.key()] = e
+	}
+}
+
+// Get retrieves the translation of the text using method, false is returned if the
+// text has not been translated.
+func (s *Store) Get(text string, method translation.Method) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.entries[Key(text, method.Name())]
+	return e.Translation, ok
+}
+
+// Put stores the translation of the text using method.
+func (s *Store) Put(text string, method translation.Method, translated string) error {
+	if method.Name() == "" {
+		return fmt.Errorf("unknown translation method: %d", method)
+	}
+	return s.put(Entry{Text: text, Method: method.Name(), Translation: translated})
+}
+
+// put appends the entry to the log, an entry which is already stored is not appended again.
+func (s *Store) put(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := e.key()
+	if existing, ok := s.entries[k]; ok && existing == e {
+		return nil
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := s.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	s.entries[k] = e
+	return nil
+}
+
+// Len the amount of entries in the memory.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Import imports the JSON encoded entries from r, one per line, i.e from Export. The
+// amount of entries imported is returned. Entries for an unknown method are rejected.
+func (s *Store) Import(r io.Reader) (int, error) {
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+
+	var n int
+	for {
+		var e Entry
+		err := d.Decode(&e)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, fmt.Errorf("entry %d: %w", n+1, err)
+		}
+
+		if strings.TrimSpace(e.Text) == "" || e.Translation == "" {
+			return n, fmt.Errorf("entry %d: text and translation are required", n+1)
+		}
+		if _, err := translation.ParseMethod(e.Method); err != nil {
+			return n, fmt.Errorf("entry %d: %w", n+1, err)
+		}
+		e.Method = strings.ToLower(e.Method)
+
+		if err := s.put(e); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// Export exports the entries to w as JSON, one per line, sorted by method and text.
+func (s *Store) Export(w io.Writer) error {
+	s.mu.RLock()
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Method != entries[j].Method {
+			return entries[i].Method < entries[j].Method
+		}
+		return entries[i].Text < entries[j].Text
+	})
+
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// translator a translation.Translator implementation which consults the memory before
+// calling the next translator.
+type translator struct {
+	s    *Store
+	next translation.Translator
+}
+
+// Translate implements translation.Translator interface.
+//
+// a successful translation from the next translator is stored, a failure to store the
+// translation does not fail the translation.
+func (t *translator) Translate(ctx context.Context, input string, method translation.Method) (string, error) {
+	if out, ok := t.s.Get(input, method); ok {
+		return out, nil
+	}
+
+	out, err := t.next.Translate(ctx, input, method)
+	if err != nil || out == "" {
+		return out, err
+	}
+
+	_ = t.s.Put(input, method, out)
+	return out, nil
+}
+
+// Translator initialises a translator which returns the translation from the memory if the
+// input has been translated before, otherwise next is called and its translation stored.
+func (s *Store) Translator(next translation.Translator) translation.Translator {
+	return &translator{s: s, next: next}
+}
@@ -0,0 +1,291 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// open opens a new translation memory in a temporary directory.
+func open(t *testing.T) (*Store, string) {
+	path := filepath.Join(t.TempDir(), "memory.jsonl")
+	s, err := Open(path)
+	require.NoError(t, err)
+	return s, path
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, Key("a  test\ndescription ", "yoda"), Key("a test description", "Yoda"))
+	assert.NotEqual(t, Key("a test description", "yoda"), Key("a test description", "shakespeare"))
+	assert.NotEqual(t, Key("a test description", "yoda"), Key("A test description", "yoda"))
+}
+
+func TestStore(t *testing.T) {
+	s, path := open(t)
+
+	_, ok := s.Get("a test description", translation.Yoda)
+	assert.False(t, ok)
+
+	require.NoError(t, s.Put("a test description", translation.Yoda, "a test description, yes"))
+	require.NoError(t, s.Put("a test description", translation.Yoda, "a test description, yes"))
+	require.NoError(t, s.Put("a test description", translation.Shakespeare, "a test description, verily"))
+	assert.Error(t, s.Put("a test description", translation.Method(-1), "unknown"))
+	require.NoError(t, s.Close())
+
+	// the same translation is only appended once.
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(b), "\n"))
+
+	// the translations are persisted between restarts.
+	s, err = Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	out, ok := s.Get("a test description", translation.Yoda)
+	assert.True(t, ok)
+	assert.Equal(t, "a test description, yes", out)
+	assert.Equal(t, 2, s.Len())
+}
+
+func TestOpen(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Contents string
+		Expected func(t *testing.T, s *Store, path string, err error)
+	}{
+		{
+			Name:     "Empty",
+			Contents: "",
+			Expected: func(t *testing.T, s *Store, path string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 0, s.Len())
+			},
+		},
+		{
+			Name:     "IncompleteEntry",
+			Contents: `{"text":"a","method":"yoda","translation":"b"}` + "\n" + `{"text":"c","meth`,
+			Expected: func(t *testing.T, s *Store, path string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 1, s.Len())
+
+				// the incomplete entry is overwritten by the next entry.
+				require.NoError(t, s.Put("c", translation.Yoda, "d"))
+				b, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t,
+					`{"text":"a","method":"yoda","translation":"b"}`+"\n"+
+						`{"text":"c","method":"yoda","translation":"d"}`+"\n",
+					string(b),
+				)
+			},
+		},
+		{
+			Name:     "InvalidEntry",
+			Contents: `{"text":"a","method":"yoda","translation":"b"}` + "\n" + "invalid\n",
+			Expected: func(t *testing.T, s *Store, path string, err error) {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "line 2")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			path := filepath.Join(st.TempDir(), "memory.jsonl")
+			require.NoError(st, ioutil.WriteFile(path, []byte(tc.Contents), 0644))
+
+			s, err := Open(path)
+			if s != nil {
+				defer s.Close()
+			}
+			tc.Expected(st, s, path, err)
+		})
+	}
+}
+
+func TestOpen_Shared(t *testing.T) {
+	a, path := open(t)
+	defer a.Close()
+	b, err := Open(path)
+	require.NoError(t, err)
+	defer b.Close()
+
+	// entries appended by each store are written to the end of the file rather than
+	// overwriting the entries appended by the other store.
+	require.NoError(t, a.Put("a", translation.Yoda, "b"))
+	require.NoError(t, b.Put("c", translation.Yoda, "d"))
+	require.NoError(t, a.Put("e", translation.Shakespeare, "f"))
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, 3, s.Len())
+}
+
+func TestOpen_Directory(t *testing.T) {
+	_, err := Open(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestStore_Import(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Input    string
+		Expected func(t *testing.T, s *Store, n int, err error)
+	}{
+		{
+			Name: "Valid",
+			Input: `{"text":"a","method":"yoda","translation":"b"}` + "\n" +
+				`{"text":"c","method":"Shakespeare","translation":"d"}`,
+			Expected: func(t *testing.T, s *Store, n int, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 2, n)
+
+				out, ok := s.Get("c", translation.Shakespeare)
+				assert.True(t, ok)
+				assert.Equal(t, "d", out)
+			},
+		},
+		{
+			Name:  "UnknownMethod",
+			Input: `{"text":"a","method":"yoda","translation":"b"}{"text":"a","method":"invalid","translation":"b"}`,
+			Expected: func(t *testing.T, s *Store, n int, err error) {
+				assert.EqualError(t, err, "entry 2: unknown translation method: invalid")
+				assert.Equal(t, 1, n)
+			},
+		},
+		{
+			Name:  "UnknownField",
+			Input: `{"text":"a","method":"yoda","translated":"b"}`,
+			Expected: func(t *testing.T, s *Store, n int, err error) {
+				assert.Error(t, err)
+				assert.Equal(t, 0, s.Len())
+			},
+		},
+		{
+			Name:  "MissingTranslation",
+			Input: `{"text":"a","method":"yoda"}`,
+			Expected: func(t *testing.T, s *Store, n int, err error) {
+				assert.EqualError(t, err, "entry 1: text and translation are required")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			s, _ := open(st)
+			defer s.Close()
+
+			n, err := s.Import(strings.NewReader(tc.Input))
+			tc.Expected(st, s, n, err)
+		})
+	}
+}
+
+func TestStore_Export(t *testing.T) {
+	s, _ := open(t)
+	defer s.Close()
+
+	require.NoError(t, s.Put("b", translation.Yoda, "c"))
+	require.NoError(t, s.Put("a", translation.Yoda, "b"))
+	require.NoError(t, s.Put("a", translation.Shakespeare, "d"))
+
+	var b bytes.Buffer
+	require.NoError(t, s.Export(&b))
+	assert.Equal(t,
+		`{"text":"a","method":"shakespeare","translation":"d"}`+"\n"+
+			`{"text":"a","method":"yoda","translation":"b"}`+"\n"+
+			`{"text":"b","method":"yoda","translation":"c"}`+"\n",
+		b.String(),
+	)
+
+	// an export can be imported into another memory.
+	other, _ := open(t)
+	defer other.Close()
+
+	n, err := other.Import(&b)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestStore_Translator(t *testing.T) {
+	errTranslation := errors.New("rate limited")
+
+	tt := []struct {
+		Name     string
+		Setup    func(t *testing.T, s *Store)
+		Next     func(calls *int) translation.Translator
+		Expected func(t *testing.T, s *Store, out string, err error, calls int)
+	}{
+		{
+			Name:  "Hit",
+			Setup: func(t *testing.T, s *Store) { require.NoError(t, s.Put("input", translation.Yoda, "stored")) },
+			Next: func(calls *int) translation.Translator {
+				return translation.TranslatorFunc(func(context.Context, string, translation.Method) (string, error) {
+					*calls++
+					return "translated", nil
+				})
+			},
+			Expected: func(t *testing.T, s *Store, out string, err error, calls int) {
+				assert.NoError(t, err)
+				assert.Equal(t, "stored", out)
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			Name:  "Miss",
+			Setup: func(t *testing.T, s *Store) {},
+			Next: func(calls *int) translation.Translator {
+				return translation.TranslatorFunc(func(context.Context, string, translation.Method) (string, error) {
+					*calls++
+					return "translated", nil
+				})
+			},
+			Expected: func(t *testing.T, s *Store, out string, err error, calls int) {
+				assert.NoError(t, err)
+				assert.Equal(t, "translated", out)
+				assert.Equal(t, 1, calls)
+
+				stored, ok := s.Get("input", translation.Yoda)
+				assert.True(t, ok)
+				assert.Equal(t, "translated", stored)
+			},
+		},
+		{
+			Name:  "Error",
+			Setup: func(t *testing.T, s *Store) {},
+			Next: func(calls *int) translation.Translator {
+				return translation.TranslatorFunc(func(context.Context, string, translation.Method) (string, error) {
+					*calls++
+					return "", errTranslation
+				})
+			},
+			Expected: func(t *testing.T, s *Store, out string, err error, calls int) {
+				assert.Equal(t, errTranslation, err)
+				assert.Equal(t, 0, s.Len())
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			s, _ := open(st)
+			defer s.Close()
+			tc.Setup(st, s)
+
+			var calls int
+			out, err := s.Translator(tc.Next(&calls)).Translate(context.Background(), "input", translation.Yoda)
+			tc.Expected(st, s, out, err, calls)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+// Package offline provides a translator which performs the yoda and shakespeare translations
+// locally without any network requests, i.e as a fallback when the translation API is rate-limited.
+//
+// the translations are deterministic and rule-based, a dictionary of word and phrase substitutions
+// is applied to the text and, for yoda, clauses are reordered so that the verb comes last.
+package offline
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// maxPhrase the maximum number of words in a phrase substitution.
+const maxPhrase = 3
+
+// translator a translation.Translator implementation which translates locally.
+type translator struct{}
+
+// New initialises a translator which performs the yoda and shakespeare translations locally,
+// translation.ErrUnsupportedMethod is returned for any other method.
+func New() translation.Translator { return translator{} }
+
+// Translate implements translation.Translator interface.
+func (translator) Translate(_ context.Context, input string, method translation.Method) (string, error) {
+	switch method {
+	case translation.Yoda:
+		return Yoda(input), nil
+	case translation.Shakespeare:
+		return Shakespeare(input), nil
+	}
+	return "", fmt.Errorf("%w: %s", translation.ErrUnsupportedMethod, method)
+}
+
+// translate applies fn to the input, any HTML entities in the input, i.e those escaped by
+// api.Normalise, are unescaped beforehand and the output is escaped to match.
+func translate(input string, fn func(string) string) string {
+	text := html.UnescapeString(input)
+	out := fn(text)
+	if text != input {
+		out = html.EscapeString(out)
+	}
+	return out
+}
+
+// word a single whitespace separated word split into any leading punctuation, the
+// word itself and any trailing punctuation, i.e `"Hello,` is `"`, `Hello` and `,`.
+type word struct {
+	pre, core, suf string
+}
+
+// String implements the fmt.Stringer interface.
+func (w word) String() string { return w.pre + w.core + w.suf }
+
+// isWordRune determines whether r is a letter or digit, a word is trimmed to its first
+// and last letter or digit so apostrophes and hyphens within it are kept, i.e fire-type.
+func isWordRune(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+// split splits the input into words.
+func split(input string) []word {
+	fields := strings.Fields(input)
+	words := make([]word, 0, len(fields))
+	for _, f := range fields {
+		start, end := strings.IndexFunc(f, isWordRune), strings.LastIndexFunc(f, isWordRune)
+		if start < 0 {
+			// punctuation on its own, i.e a dash.
+			words = append(words, word{pre: f})
+			continue
+		}
+
+		// include the full width of the last rune.
+		_, size := utf8.DecodeRuneInString(f[end:])
+		end += size
+		words = append(words, word{pre: f[:start], core: f[start:end], suf: f[end:]})
+	}
+	return words
+}
+
+// join joins the words back into text.
+func join(words []word) string {
+	parts := make([]string, 0, len(words))
+	for _, w := range words {
+		if s := w.String(); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// substitute replaces any word or phrase in the dictionary, the longest phrase is replaced
+// first. Phrases only match where there is no punctuation between the words, the case of
+// the first word and the punctuation surrounding the phrase is kept.
+func substitute(words []word, dict map[string]string) []word {
+	out := make([]word, 0, len(words))
+	for i := 0; i < len(words); {
+		n, repl := match(words[i:], dict)
+		if n == 0 {
+			out = append(out, words[i])
+			i++
+			continue
+		}
+
+		first, last := words[i], words[i+n-1]
+		rw := strings.Fields(repl)
+		for j, r := range rw {
+			w := word{core: r}
+			switch {
+			case j == 0:
+				w.pre, w.core = first.pre, matchCase(first.core, r)
+			case isUpper(first.core):
+				w.core = strings.ToUpper(r)
+			}
+			if j == len(rw)-1 {
+				w.suf = last.suf
+			}
+			out = append(out, w)
+		}
+		i += n
+	}
+	return out
+}
+
+// match finds the longest phrase at the start of words which is in the dictionary, the number
+// of words matched and the replacement is returned, zero is returned if no phrase matches.
+func match(words []word, dict map[string]string) (int, string) {
+	for n := maxPhrase; n > 0; n-- {
+		if n > len(words) {
+			continue
+		}
+
+		parts := make([]string, 0, n)
+		for j, w := range words[:n] {
+			// punctuation is only allowed before the first and after the last word.
+			if w.core == "" || (j > 0 && w.pre != "") || (j < n-1 && w.suf != "") {
+				break
+			}
+			parts = append(parts, strings.ToLower(w.core))
+		}
+		if len(parts) != n {
+			continue
+		}
+
+		if repl, ok := dict[strings.Join(parts, " ")]; ok {
+			return n, repl
+		}
+	}
+	return 0, ""
+}
+
+// matchCase applies the case of src to repl, an upper case src gives an upper case repl
+// and a capitalised src gives a capitalised repl.
+func matchCase(src, repl string) string {
+	switch {
+	case isUpper(src):
+		return strings.ToUpper(repl)
+	case isCapitalised(src):
+		return capitalise(repl)
+	}
+	return repl
+}
+
+// isUpper determines whether s is an upper case word, a single letter, i.e I, is not.
+func isUpper(s string) bool {
+	return len([]rune(s)) > 1 && strings.ToUpper(s) == s && strings.ToLower(s) != s
+}
+
+// isCapitalised determines whether the first letter of s is upper case.
+func isCapitalised(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return unicode.IsUpper(r)
+		}
+	}
+	return false
+}
+
+// capitalise converts the first letter of s to upper case, i.e 'tis is 'Tis.
+func capitalise(s string) string { return mapFirst(s, unicode.ToUpper) }
+
+// decapitalise converts the first letter of s to lower case, words which are not simply
+// capitalised are kept, i.e I or POKéMON.
+func decapitalise(s string) string {
+	if s == "I" || strings.HasPrefix(s, "I'") {
+		return s
+	}
+
+	r := []rune(s)
+	if len(r) > 1 && string(r[1:]) != strings.ToLower(string(r[1:])) {
+		return s
+	}
+	return mapFirst(s, unicode.ToLower)
+}
+
+// mapFirst applies fn to the first letter of s.
+func mapFirst(s string, fn func(rune) rune) string {
+	r := []rune(s)
+	for i, c := range r {
+		if unicode.IsLetter(c) {
+			r[i] = fn(c)
+			break
+		}
+	}
+	return string(r)
+}
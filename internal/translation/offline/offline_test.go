@@ -0,0 +1,157 @@
+package offline
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api"
+	"github.com/jacklaaa89/pokeapi/internal/translation"
+)
+
+// update used to regenerate the golden files, i.e go test ./internal/translation/offline -update
+var update = flag.Bool("update", false, "if true, the golden files are updated with the current output.")
+
+func TestTranslator_Translate(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Method   translation.Method
+		Expected func(t *testing.T, out string, err error)
+	}{
+		{
+			Name:   "Yoda",
+			Method: translation.Yoda,
+			Expected: func(t *testing.T, out string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Most strong, you are.", out)
+			},
+		},
+		{
+			Name:   "Shakespeare",
+			Method: translation.Shakespeare,
+			Expected: func(t *testing.T, out string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Thou art most mighty.", out)
+			},
+		},
+		{
+			Name:   "Unsupported",
+			Method: translation.Method(-1),
+			Expected: func(t *testing.T, out string, err error) {
+				assert.True(t, errors.Is(err, translation.ErrUnsupportedMethod))
+				assert.Empty(t, out)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			out, err := New().Translate(context.Background(), "You are very strong.", tc.Method)
+			tc.Expected(st, out, err)
+		})
+	}
+}
+
+func TestYoda(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{Name: "Empty", Input: "", Expected: ""},
+		{Name: "Reordered", Input: "It can use its psychic powers.", Expected: "Use its psychic powers, it can."},
+		{Name: "Question", Input: "Is it strong?", Expected: "Is it strong?"},
+		{Name: "NoVerb", Input: "It flies at night.", Expected: "It flies at night."},
+		{Name: "Conjunction", Input: "When it is angry, it bites.", Expected: "When it is angry, it bites."},
+		{Name: "VerbOnly", Input: "It is.", Expected: "It is."},
+		{Name: "Clauses", Input: "It is fast, and it is strong.", Expected: "Fast, it is, and it is strong."},
+		{Name: "SecondClause", Input: "Be careful; it can bite.", Expected: "Be careful; bite, it can."},
+		{Name: "Quoted", Input: `"It is mine," he said.`, Expected: `"Mine, it is," he said.`},
+		{Name: "ProperNoun", Input: "POKéMON can evolve.", Expected: "Evolve, POKéMON can."},
+		{Name: "Substitution", Input: "Children are afraid of it.", Expected: "Fearful of it, younglings are."},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			assert.Equal(st, tc.Expected, Yoda(tc.Input))
+		})
+	}
+}
+
+func TestShakespeare(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{Name: "Empty", Input: "", Expected: ""},
+		{Name: "Phrase", Input: "It is strong.", Expected: "'Tis mighty."},
+		{Name: "PhraseSplitByPunctuation", Input: "It, is strong.", Expected: "It, is mighty."},
+		{Name: "LongestPhrase", Input: "You are here in order to win.", Expected: "Thou art here to win."},
+		{Name: "UpperCase", Input: "YOU ARE FAST!", Expected: "THOU ART SWIFT!"},
+		{Name: "Capitalised", Input: "Never again.", Expected: "Ne'er again."},
+		{Name: "Punctuation", Input: `("your friend")`, Expected: `("thy companion")`},
+		{Name: "Unknown", Input: "The plant sprouts.", Expected: "The plant sprouts."},
+		{Name: "Escaped", Input: "&#34;It is mine.&#34;", Expected: "&#34;&#39;Tis mine.&#34;"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			assert.Equal(st, tc.Expected, Shakespeare(tc.Input))
+		})
+	}
+}
+
+// TestGolden translates each of the descriptions in testdata/descriptions.txt, which are
+// quoted as they contain the control characters returned by PokeAPI, once normalised using
+// api.Normalise and compares the output with the golden file for each method.
+func TestGolden(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "descriptions.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var descriptions []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		d, err := strconv.Unquote(s.Text())
+		require.NoError(t, err)
+		descriptions = append(descriptions, api.Normalise(d))
+	}
+	require.NoError(t, s.Err())
+
+	tt := []struct {
+		Name string
+		Fn   func(string) string
+	}{
+		{Name: "yoda", Fn: Yoda},
+		{Name: "shakespeare", Fn: Shakespeare},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			var b strings.Builder
+			for _, d := range descriptions {
+				b.WriteString(tc.Fn(d) + "\n")
+			}
+
+			path := filepath.Join("testdata", tc.Name+".golden")
+			if *update {
+				require.NoError(st, ioutil.WriteFile(path, []byte(b.String()), 0644))
+			}
+
+			expected, err := ioutil.ReadFile(path)
+			require.NoError(st, err)
+			assert.Equal(st, string(expected), b.String())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package offline
+
+// shakespeare the word and phrase substitutions for the shakespeare translation.
+var shakespeare = map[string]string{
+	// phrases.
+	"it is":       "'tis",
+	"it was":      "'twas",
+	"is it":       "is't",
+	"you are":     "thou art",
+	"are you":     "art thou",
+	"you were":    "thou wert",
+	"you have":    "thou hast",
+	"have you":    "hast thou",
+	"you will":    "thou wilt",
+	"will you":    "wilt thou",
+	"you can":     "thou canst",
+	"can you":     "canst thou",
+	"you do":      "thou dost",
+	"do you":      "dost thou",
+	"does not":    "doth not",
+	"in order to": "to",
+
+	// words.
+	"you":       "thee",
+	"your":      "thy",
+	"yours":     "thine",
+	"yourself":  "thyself",
+	"has":       "hath",
+	"does":      "doth",
+	"says":      "saith",
+	"seems":     "seemeth",
+	"before":    "ere",
+	"often":     "oft",
+	"over":      "o'er",
+	"never":     "ne'er",
+	"ever":      "e'er",
+	"even":      "e'en",
+	"between":   "betwixt",
+	"among":     "amongst",
+	"while":     "whilst",
+	"perhaps":   "perchance",
+	"maybe":     "mayhap",
+	"yes":       "aye",
+	"nothing":   "naught",
+	"anything":  "aught",
+	"why":       "wherefore",
+	"hello":     "good morrow",
+	"hi":        "hail",
+	"goodbye":   "farewell",
+	"listen":    "hark",
+	"enemy":     "foe",
+	"enemies":   "foes",
+	"friend":    "companion",
+	"friends":   "companions",
+	"girl":      "lass",
+	"boy":       "lad",
+	"very":      "most",
+	"quickly":   "swiftly",
+	"fast":      "swift",
+	"big":       "great",
+	"huge":      "mighty",
+	"strong":    "mighty",
+	"beautiful": "fair",
+	"pretty":    "fair",
+	"dangerous": "perilous",
+	"kill":      "slay",
+	"kills":     "slays",
+	"killed":    "slain",
+	"sleep":     "slumber",
+	"sleeps":    "slumbers",
+	"sleeping":  "slumbering",
+	"fight":     "duel",
+	"fights":    "duels",
+	"angry":     "wrathful",
+	"anger":     "wrath",
+	"scared":    "afeard",
+	"afraid":    "afeard",
+	"strange":   "wondrous",
+	"believe":   "trow",
+}
+
+// Shakespeare translates the input into the style of shakespeare, i.e `You are very strong.`
+// is `Thou art most mighty.`
+func Shakespeare(input string) string {
+	return translate(input, func(text string) string { return join(substitute(split(text), shakespeare)) })
+}
@@ -0,0 +1,120 @@
+package offline
+
+import "strings"
+
+// maxSubject the maximum number of words before the verb in a clause which is reordered.
+const maxSubject = 3
+
+// yoda the word and phrase substitutions for the yoda translation.
+var yoda = map[string]string{
+	"very":     "most",
+	"really":   "truly",
+	"hello":    "greetings",
+	"goodbye":  "farewell",
+	"child":    "youngling",
+	"children": "younglings",
+	"kid":      "youngling",
+	"kids":     "younglings",
+	"student":  "padawan",
+	"students": "padawans",
+	"teacher":  "master",
+	"teachers": "masters",
+	"afraid":   "fearful",
+	"scared":   "fearful",
+}
+
+// verbs the auxiliary and linking verbs which are moved to the end of a clause.
+var verbs = set(
+	"is", "are", "was", "were", "am", "can", "could", "will", "would", "shall", "should",
+	"may", "might", "must", "has", "have", "had", "does", "do", "did",
+)
+
+// conjunctions the words which cannot be part of the subject of a clause, i.e `when it is angry`
+// is not reordered.
+var conjunctions = set(
+	"and", "or", "but", "so", "if", "when", "whenever", "while", "as", "because", "since", "after",
+	"before", "until", "although", "though", "that", "which", "who", "where", "than", "what", "how",
+)
+
+// set initialises a set containing the values.
+func set(values ...string) map[string]bool {
+	s := make(map[string]bool, len(values))
+	for _, v := range values {
+		s[v] = true
+	}
+	return s
+}
+
+// Yoda translates the input into the style of yoda, clauses which start with a short subject
+// followed by an auxiliary verb are reordered, i.e `It is very strong.` is `Most strong, it is.`
+func Yoda(input string) string { return translate(input, yodaText) }
+
+// yodaText translates the unescaped text into the style of yoda.
+func yodaText(text string) string {
+	words := substitute(split(text), yoda)
+
+	out := make([]word, 0, len(words)+1)
+	start, sentence := 0, true
+	for i, w := range words {
+		// a clause ends at any clause or sentence punctuation, or at the end of the input.
+		if i < len(words)-1 && !strings.ContainsAny(w.suf, ",;:.!?") {
+			continue
+		}
+
+		out = append(out, reorder(words[start:i+1], sentence)...)
+		start, sentence = i+1, strings.ContainsAny(w.suf, ".!?")
+	}
+	return join(out)
+}
+
+// reorder moves the subject and verb of the clause to the end of the clause, i.e
+// `it can use psychic powers` is `use psychic powers, it can`. The clause is returned
+// unchanged when it does not start with a short subject followed by a verb.
+func reorder(clause []word, sentence bool) []word {
+	v := verb(clause)
+	if v < 0 {
+		return clause
+	}
+
+	subject, rest := clause[:v+1], clause[v+1:]
+	last := rest[len(rest)-1]
+
+	out := make([]word, 0, len(clause))
+	out = append(out, rest...)
+	out[0].pre = subject[0].pre + out[0].pre
+	out[len(out)-1].suf = ","
+	out = append(out, subject...)
+	out[len(rest)].pre = ""
+	out[len(out)-1].suf = last.suf
+
+	if sentence {
+		out[0].core = capitalise(out[0].core)
+		out[len(rest)].core = decapitalise(out[len(rest)].core)
+	}
+	return out
+}
+
+// verb finds the index of the verb which follows the subject of the clause, -1 is returned
+// if the clause does not start with a short subject followed by a verb.
+func verb(clause []word) int {
+	for i, w := range clause {
+		if i > maxSubject || i == len(clause)-1 {
+			break
+		}
+
+		// the subject and verb cannot be split by punctuation, other than before the clause.
+		core := strings.ToLower(w.core)
+		if core == "" || w.suf != "" || (i > 0 && w.pre != "") || conjunctions[core] {
+			break
+		}
+
+		if verbs[core] {
+			if i == 0 {
+				// a question, i.e `is it strong?`, has no subject before the verb.
+				break
+			}
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,184 @@
+package translation
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/interceptor"
+	"github.com/jacklaaa89/pokeapi/internal/api/log"
+)
+
+// the headers the translation API sends describing the quota.
+const (
+	limitHeader      = "X-RateLimit-Limit"
+	remainingHeader  = "X-RateLimit-Remaining"
+	resetHeader      = "X-RateLimit-Reset"
+	retryAfterHeader = "Retry-After"
+	fromCacheHeader  = "X-From-Cache"
+)
+
+// defaultQuotaWindow the duration calls are skipped for once the quota is exhausted when
+// the translation API does not say when the quota resets, the free plan resets hourly.
+const defaultQuotaWindow = time.Hour
+
+// epochThreshold the value above which X-RateLimit-Reset is treated as a unix timestamp
+// rather than the amount of seconds until the quota resets.
+const epochThreshold = 1000000000
+
+// now the function used to retrieve the current time, overridden in tests.
+var now = time.Now
+
+// Quota the state of the translation API quota, as last reported by the translation API.
+type Quota struct {
+	// Limit the amount of requests allowed in the current window, zero if unknown.
+	Limit int `json:"limit"`
+	// Remaining the amount of requests remaining in the current window.
+	Remaining int `json:"remaining"`
+	// Total the total amount of successful translations reported by the translation API.
+	Total int64 `json:"total"`
+	// Exhausted whether calls are skipped until the quota resets.
+	Exhausted bool `json:"exhausted"`
+	// Reset the time the quota resets, if known.
+	Reset *time.Time `json:"reset,omitempty"`
+	// Updated the time the quota was last reported, nil if no request has been made.
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+// quota tracks the quota reported by the translation API.
+type quota struct {
+	mu  sync.RWMutex
+	q   Quota
+	log log.Logger
+}
+
+// state retrieves the current quota, the quota is no longer exhausted once it resets.
+func (q *quota) state() Quota {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	s := q.q
+	s.Exhausted = q.exhausted()
+	return s
+}
+
+// exhausted determines whether the quota is exhausted and has not yet reset.
+func (q *quota) exhausted() bool {
+	return q.q.Exhausted && (q.q.Reset == nil || now().Before(*q.q.Reset))
+}
+
+// BeforeSend skips the attempt when the quota is exhausted, the call fails with
+// errors.CodeRateLimitExceeded without a request being made.
+func (q *quota) BeforeSend(_ context.Context, c *interceptor.Call) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if !q.exhausted() {
+		return nil
+	}
+
+	err := errors.FromRequest(c.Request)
+	err.Code, err.Source = errors.CodeRateLimitExceeded, "translation quota exhausted until "+q.q.Reset.Format(time.RFC3339)
+	return err
+}
+
+// AfterResponse updates the quota from the headers of each response, responses served
+// from the cache are ignored as their headers describe the quota at the time they were stored.
+func (q *quota) AfterResponse(_ context.Context, c *interceptor.Call) error {
+	res := c.Response
+	if res == nil || res.Header.Get(fromCacheHeader) != "" {
+		return nil
+	}
+
+	limit, hasLimit := header(res.Header, limitHeader)
+	remaining, hasRemaining := header(res.Header, remainingHeader)
+	limited := res.StatusCode == http.StatusTooManyRequests
+	if !hasLimit && !hasRemaining && !limited {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := now()
+	q.q.Updated = &t
+	if hasLimit {
+		q.q.Limit = limit
+	}
+	if hasRemaining {
+		q.q.Remaining = remaining
+		q.warn()
+	}
+
+	if !limited && (!hasRemaining || remaining > 0) {
+		q.q.Exhausted, q.q.Reset = false, nil
+		return nil
+	}
+
+	wasExhausted := q.exhausted()
+	q.q.Exhausted, q.q.Remaining = true, 0
+	q.q.Reset = reset(res.Header, t)
+	if !wasExhausted {
+		q.log.Warnf("Translation quota exhausted, skipping translations until %v", q.q.Reset.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// AfterDecode records the total amount of successful translations from the response.
+func (q *quota) AfterDecode(_ context.Context, c *interceptor.Call) error {
+	r, ok := c.Value.(*response)
+	if !ok || c.Err != nil || r.Success.Total == 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.q.Total = r.Success.Total
+	return nil
+}
+
+// BeforeEncode implements interceptor.Interceptor interface.
+func (q *quota) BeforeEncode(context.Context, *interceptor.Call) error { return nil }
+
+// warn logs a warning as the quota nears zero, once the remaining requests are
+// within a tenth of the limit.
+func (q *quota) warn() {
+	threshold := q.q.Limit / 10
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	if q.q.Remaining > 0 && q.q.Remaining <= threshold {
+		q.log.Warnf("Translation quota low, %d of %d requests remaining", q.q.Remaining, q.q.Limit)
+	}
+}
+
+// header parses an integer header, false is returned if the header is not defined or invalid.
+func header(h http.Header, key string) (int, bool) {
+	v, err := strconv.Atoi(h.Get(key))
+	if err != nil || v < 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+// reset determines when the quota resets from the X-RateLimit-Reset header, which is either
+// a unix timestamp or the amount of seconds until the reset, or the Retry-After header. The
+// default quota window is used if neither header is defined.
+func reset(h http.Header, t time.Time) *time.Time {
+	r := t.Add(defaultQuotaWindow)
+	if v, ok := header(h, resetHeader); ok {
+		if v > epochThreshold {
+			r = time.Unix(int64(v), 0)
+		} else {
+			r = t.Add(time.Duration(v) * time.Second)
+		}
+	} else if v, ok := header(h, retryAfterHeader); ok {
+		r = t.Add(time.Duration(v) * time.Second)
+	} else if d, err := http.ParseTime(h.Get(retryAfterHeader)); err == nil {
+		r = d
+	}
+	return &r
+}
@@ -0,0 +1,237 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	encoding "encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacklaaa89/pokeapi/internal/api/errors"
+	"github.com/jacklaaa89/pokeapi/internal/api/log/fmt"
+	"github.com/jacklaaa89/pokeapi/internal/api/opts"
+	"github.com/jacklaaa89/pokeapi/internal/api/redact"
+)
+
+// quotaResponse a response from the translation API describing the quota.
+type quotaResponse struct {
+	code   int
+	header map[string]string
+	total  int64
+}
+
+// quotaServer starts a server which responds with each of the responses in order, the
+// amount of requests received is recorded in requests.
+func quotaServer(t *testing.T, requests *int, responses ...quotaResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		require.Less(t, *requests, len(responses))
+		r := responses[*requests]
+		*requests++
+
+		for k, v := range r.header {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(r.code)
+		_ = encoding.NewEncoder(w).Encode(&response{
+			Success:  responseSuccessData{Total: r.total},
+			Contents: responseContents{Translated: "translated"},
+		})
+	}))
+}
+
+func TestClient_Quota(t *testing.T) {
+	current := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	tt := []struct {
+		Name      string
+		Responses []quotaResponse
+		Calls     int
+		Expected  func(t *testing.T, c *Client, requests int, err error, logs string)
+	}{
+		{
+			Name:  "NoRequests",
+			Calls: 0,
+			Expected: func(t *testing.T, c *Client, requests int, err error, logs string) {
+				assert.Equal(t, Quota{}, c.Quota())
+			},
+		},
+		{
+			Name: "Tracked",
+			Responses: []quotaResponse{
+				{code: http.StatusOK, total: 5, header: map[string]string{limitHeader: "60", remainingHeader: "55"}},
+			},
+			Calls: 1,
+			Expected: func(t *testing.T, c *Client, requests int, err error, logs string) {
+				require.NoError(t, err)
+				q := c.Quota()
+				assert.Equal(t, 60, q.Limit)
+				assert.Equal(t, 55, q.Remaining)
+				assert.Equal(t, int64(5), q.Total)
+				assert.False(t, q.Exhausted)
+				assert.Nil(t, q.Reset)
+				assert.Equal(t, current, *q.Updated)
+				assert.Empty(t, logs)
+			},
+		},
+		{
+			Name: "NearlyExhausted",
+			Responses: []quotaResponse{
+				{code: http.StatusOK, total: 1, header: map[string]string{limitHeader: "60", remainingHeader: "6"}},
+			},
+			Calls: 1,
+			Expected: func(t *testing.T, c *Client, requests int, err error, logs string) {
+				require.NoError(t, err)
+				assert.Contains(t, logs, "Translation quota low, 6 of 60 requests remaining")
+			},
+		},
+		{
+			Name: "ExhaustedSkipsCalls",
+			Responses: []quotaResponse{
+				{code: http.StatusOK, total: 1, header: map[string]string{
+					limitHeader: "5", remainingHeader: "0", resetHeader: "600",
+				}},
+			},
+			Calls: 3,
+			Expected: func(t *testing.T, c *Client, requests int, err error, logs string) {
+				assert.True(t, errors.IsRateLimited(err))
+				assert.Contains(t, err.Error(), "translation quota exhausted until 2021-06-01T12:10:00Z")
+				assert.Equal(t, 1, requests)
+
+				q := c.Quota()
+				assert.True(t, q.Exhausted)
+				assert.Equal(t, current.Add(10*time.Minute), q.Reset.UTC())
+				assert.Contains(t, logs, "Translation quota exhausted")
+			},
+		},
+		{
+			Name: "RateLimited",
+			Responses: []quotaResponse{
+				{code: http.StatusTooManyRequests, header: map[string]string{retryAfterHeader: "120"}},
+			},
+			Calls: 2,
+			Expected: func(t *testing.T, c *Client, requests int, err error, logs string) {
+				assert.True(t, errors.IsRateLimited(err))
+				assert.Equal(t, 1, requests)
+				assert.Equal(t, current.Add(2*time.Minute), *c.Quota().Reset)
+			},
+		},
+		{
+			Name: "RateLimitedUnknownReset",
+			Responses: []quotaResponse{
+				{code: http.StatusTooManyRequests},
+			},
+			Calls: 1,
+			Expected: func(t *testing.T, c *Client, requests int, err error, logs string) {
+				assert.True(t, errors.IsRateLimited(err))
+				assert.Equal(t, current.Add(defaultQuotaWindow), *c.Quota().Reset)
+			},
+		},
+		{
+			Name: "UnixReset",
+			Responses: []quotaResponse{
+				{code: http.StatusTooManyRequests, header: map[string]string{resetHeader: "1622552400"}},
+			},
+			Calls: 1,
+			Expected: func(t *testing.T, c *Client, requests int, err error, logs string) {
+				assert.Equal(t, time.Date(2021, 6, 1, 13, 0, 0, 0, time.UTC), c.Quota().Reset.UTC())
+			},
+		},
+		{
+			Name: "NoHeaders",
+			Responses: []quotaResponse{
+				{code: http.StatusOK, total: 1},
+			},
+			Calls: 1,
+			Expected: func(t *testing.T, c *Client, requests int, err error, logs string) {
+				require.NoError(t, err)
+				q := c.Quota()
+				assert.Nil(t, q.Updated)
+				assert.Equal(t, int64(1), q.Total)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			var requests int
+			s := quotaServer(st, &requests, tc.Responses...)
+			defer s.Close()
+
+			logs := new(bytes.Buffer)
+			c := NewWithEndpoint(s.URL, "",
+				opts.WithMaxNetworkRetries(0),
+				opts.WithLogger(fmt.NewWithOutputs(fmt.LevelWarn, logs, logs)),
+			)
+
+			var err error
+			for i := 0; i < tc.Calls; i++ {
+				_, err = c.Translate(context.Background(), "input", Yoda)
+			}
+			tc.Expected(st, c, requests, err, logs.String())
+		})
+	}
+}
+
+// masking a redact.Redactor which masks the word quota from free text.
+type masking struct{ redact.Redactor }
+
+// String implements redact.Redactor interface.
+func (masking) String(s string) string { return strings.ReplaceAll(s, "quota", redact.Mask) }
+
+// TestClient_QuotaRedacted tests that the quota warnings are redacted in the same way
+// as the log output of the client.
+func TestClient_QuotaRedacted(t *testing.T) {
+	var requests int
+	s := quotaServer(t, &requests,
+		quotaResponse{code: http.StatusOK, header: map[string]string{limitHeader: "60", remainingHeader: "6"}},
+	)
+	defer s.Close()
+
+	logs := new(bytes.Buffer)
+	c := NewWithEndpoint(s.URL, "",
+		opts.WithMaxNetworkRetries(0),
+		opts.WithLogger(fmt.NewWithOutputs(fmt.LevelWarn, logs, logs)),
+		opts.WithRedactor(masking{redact.Default()}),
+	)
+
+	_, err := c.Translate(context.Background(), "input", Yoda)
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), "Translation "+redact.Mask+" low")
+}
+
+func TestClient_QuotaReset(t *testing.T) {
+	current := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	var requests int
+	s := quotaServer(t, &requests,
+		quotaResponse{code: http.StatusTooManyRequests, header: map[string]string{retryAfterHeader: "60"}},
+		quotaResponse{code: http.StatusOK, header: map[string]string{limitHeader: "5", remainingHeader: "4"}},
+	)
+	defer s.Close()
+
+	c := NewWithEndpoint(s.URL, "", opts.WithMaxNetworkRetries(0))
+	_, err := c.Translate(context.Background(), "input", Yoda)
+	assert.True(t, errors.IsRateLimited(err))
+	assert.True(t, c.Quota().Exhausted)
+
+	// calls are made again once the quota resets.
+	current = current.Add(time.Minute)
+	assert.False(t, c.Quota().Exhausted)
+
+	out, err := c.Translate(context.Background(), "input", Yoda)
+	require.NoError(t, err)
+	assert.Equal(t, "translated", out)
+	assert.Equal(t, 4, c.Quota().Remaining)
+	assert.Equal(t, 2, requests)
+}
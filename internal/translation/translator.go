@@ -0,0 +1,65 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedMethod returned from a Translator which does not support the translation
+// method, a composite translator tries the next translator instead.
+var ErrUnsupportedMethod = errors.New("unsupported translation method")
+
+// Translator translates text using a translation method, i.e the fun-translations API.
+type Translator interface {
+	// Translate takes the input and performs the translation based on the method supplied.
+	Translate(ctx context.Context, input string, method Method) (string, error)
+}
+
+// TranslatorFunc an adapter to allow the use of an ordinary function as a Translator.
+type TranslatorFunc func(ctx context.Context, input string, method Method) (string, error)
+
+// Translate implements Translator interface.
+func (f TranslatorFunc) Translate(ctx context.Context, input string, method Method) (string, error) {
+	return f(ctx, input, method)
+}
+
+// composite a Translator implementation which tries each translator in order.
+type composite []Translator
+
+// Translate implements Translator interface.
+//
+// the output of the first translator to succeed is returned. If every translator fails
+// the error from the first translator which supports the method is returned.
+func (c composite) Translate(ctx context.Context, input string, method Method) (string, error) {
+	var first error
+	for _, t := range c {
+		out, err := t.Translate(ctx, input, method)
+		if err == nil {
+			return out, nil
+		}
+
+		if first == nil && !errors.Is(err, ErrUnsupportedMethod) {
+			first = err
+		}
+
+		// there is no point trying the next translator once the request is cancelled.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if first == nil {
+		first = fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+	return "", first
+}
+
+// Composite initialises a translator which tries each of the translators in order until
+// one succeeds, i.e a paid provider followed by a local fallback.
+func Composite(translators ...Translator) Translator {
+	if len(translators) == 1 {
+		return translators[0]
+	}
+	return composite(translators)
+}
@@ -0,0 +1,119 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fake initialises a translator which returns the supplied output and error,
+// recording each call in calls.
+func fake(calls *int, out string, err error) Translator {
+	return TranslatorFunc(func(_ context.Context, _ string, _ Method) (string, error) {
+		*calls++
+		return out, err
+	})
+}
+
+func TestComposite(t *testing.T) {
+	errFirst, errSecond := errors.New("first"), errors.New("second")
+
+	tt := []struct {
+		Name     string
+		Context  func() context.Context
+		Setup    func(calls *int) []Translator
+		Expected func(t *testing.T, out string, err error, calls int)
+	}{
+		{
+			Name: "FirstSucceeds",
+			Setup: func(calls *int) []Translator {
+				return []Translator{fake(calls, "first", nil), fake(calls, "second", nil)}
+			},
+			Expected: func(t *testing.T, out string, err error, calls int) {
+				assert.NoError(t, err)
+				assert.Equal(t, "first", out)
+				assert.Equal(t, 1, calls)
+			},
+		},
+		{
+			Name: "Fallback",
+			Setup: func(calls *int) []Translator {
+				return []Translator{fake(calls, "", errFirst), fake(calls, "second", nil)}
+			},
+			Expected: func(t *testing.T, out string, err error, calls int) {
+				assert.NoError(t, err)
+				assert.Equal(t, "second", out)
+				assert.Equal(t, 2, calls)
+			},
+		},
+		{
+			Name: "AllFail",
+			Setup: func(calls *int) []Translator {
+				return []Translator{fake(calls, "", errFirst), fake(calls, "", errSecond)}
+			},
+			Expected: func(t *testing.T, out string, err error, calls int) {
+				assert.True(t, errors.Is(err, errFirst))
+				assert.Empty(t, out)
+				assert.Equal(t, 2, calls)
+			},
+		},
+		{
+			Name: "UnsupportedSkipped",
+			Setup: func(calls *int) []Translator {
+				return []Translator{fake(calls, "", ErrUnsupportedMethod), fake(calls, "", errSecond)}
+			},
+			Expected: func(t *testing.T, out string, err error, calls int) {
+				assert.True(t, errors.Is(err, errSecond))
+				assert.Equal(t, 2, calls)
+			},
+		},
+		{
+			Name: "Unsupported",
+			Setup: func(calls *int) []Translator {
+				return []Translator{fake(calls, "", ErrUnsupportedMethod), fake(calls, "", ErrUnsupportedMethod)}
+			},
+			Expected: func(t *testing.T, out string, err error, calls int) {
+				assert.True(t, errors.Is(err, ErrUnsupportedMethod))
+				assert.EqualError(t, err, "unsupported translation method: yoda")
+			},
+		},
+		{
+			Name: "Cancelled",
+			Context: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			Setup: func(calls *int) []Translator {
+				return []Translator{fake(calls, "", context.Canceled), fake(calls, "second", nil)}
+			},
+			Expected: func(t *testing.T, out string, err error, calls int) {
+				assert.True(t, errors.Is(err, context.Canceled))
+				assert.Equal(t, 1, calls)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(st *testing.T) {
+			ctx := context.Background()
+			if tc.Context != nil {
+				ctx = tc.Context()
+			}
+
+			var calls int
+			out, err := Composite(tc.Setup(&calls)...).Translate(ctx, "input", Yoda)
+			tc.Expected(st, out, err, calls)
+		})
+	}
+}
+
+func TestComposite_Single(t *testing.T) {
+	var calls int
+	out, err := Composite(fake(&calls, "out", nil)).Translate(context.Background(), "input", Yoda)
+	assert.NoError(t, err)
+	assert.Equal(t, "out", out)
+	assert.Equal(t, 1, calls)
+}
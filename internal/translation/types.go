@@ -2,7 +2,11 @@ package translation
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 )
 
 const (
@@ -14,37 +18,164 @@ const (
 	Yoda
 )
 
-// methodMappings maps a translation method to their string representations.
-var methodMappings = map[Method]string{
-	Shakespeare: "shakespeare",
-	Yoda:        "yoda",
+// methodName the pattern a translation method name must match, the name
+// is used as the path of the translation endpoint, i.e /pirate.json.
+var methodName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// registry the registered translation methods.
+var registry = struct {
+	sync.RWMutex
+	names   map[Method]string
+	methods map[string]Method
+}{
+	names:   map[Method]string{Shakespeare: "shakespeare", Yoda: "yoda"},
+	methods: map[string]Method{"shakespeare": Shakespeare, "yoda": Yoda},
+}
+
+// Register registers a translation method offered by the translation API, i.e pirate
+// or klingon, so that it can be used at runtime. Registering a method which is already
+// registered returns the existing method. The name is case-insensitive.
+func Register(name string) (Method, error) {
+	name = normaliseMethod(name)
+	if !methodName.MatchString(name) {
+		return 0, fmt.Errorf("invalid translation method name: %q", name)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if m, ok := registry.methods[name]; ok {
+		return m, nil
+	}
+
+	m := Method(len(registry.names) + 1)
+	registry.names[m] = name
+	registry.methods[name] = m
+	return m, nil
+}
+
+// normaliseMethod normalises the name of a translation method, so that the name is
+// registered and retrieved in the same form.
+func normaliseMethod(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// RegisterAll registers each of the translation methods, i.e from configuration.
+func RegisterAll(names ...string) error {
+	for _, n := range names {
+		if _, err := Register(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Methods retrieves every registered translation method sorted by name.
+func Methods() []Method {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	out := make([]Method, 0, len(registry.names))
+	for m := range registry.names {
+		out = append(out, m)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return registry.names[out[i]] < registry.names[out[j]] })
+	return out
+}
+
+// Registry retrieves the translation methods which are available, i.e to the
+// /pokemon/{name}/translated endpoint using ?method=.
+type Registry interface {
+	// Methods retrieves every available translation method sorted by name.
+	Methods() []Method
+	// ParseMethod retrieves the available translation method with the supplied name,
+	// the name is case-insensitive.
+	ParseMethod(name string) (Method, error)
+}
+
+// registered a Registry of every registered translation method.
+type registered struct{}
+
+func (registered) Methods() []Method                       { return Methods() }
+func (registered) ParseMethod(name string) (Method, error) { return ParseMethod(name) }
+
+// Registered retrieves a Registry of every registered translation method, including
+// the methods registered after it is retrieved.
+func Registered() Registry { return registered{} }
+
+// subset a Registry of a subset of the registered translation methods.
+type subset map[Method]struct{}
+
+// Methods implements the Registry interface.
+func (s subset) Methods() []Method {
+	out := make([]Method, 0, len(s))
+	for _, m := range Methods() {
+		if _, ok := s[m]; ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// ParseMethod implements the Registry interface.
+func (s subset) ParseMethod(name string) (Method, error) {
+	m, err := ParseMethod(name)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := s[m]; !ok {
+		return 0, fmt.Errorf("unknown translation method: %s", name)
+	}
+	return m, nil
+}
+
+// NewRegistry initialises a Registry of the supplied translation methods, i.e to
+// offer a subset of the registered methods. Methods which are not registered are ignored.
+func NewRegistry(methods ...Method) Registry {
+	s := make(subset, len(methods))
+	for _, m := range methods {
+		s[m] = struct{}{}
+	}
+	return s
 }
 
 // Method represents the translation method
 type Method int
 
 // Name returns the name of the translation method as a string
-func (m Method) Name() string { return methodMappings[m] }
+// an empty string is returned if the method is not registered.
+func (m Method) Name() string {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.names[m]
+}
 
 // String implements the fmt.Stringer interface
 // returns the name.
 func (m Method) String() string { return m.Name() }
 
+// ParseMethod retrieves the registered translation method with the supplied name,
+// the name is case-insensitive.
+func ParseMethod(name string) (Method, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if m, ok := registry.methods[normaliseMethod(name)]; ok {
+		return m, nil
+	}
+	return 0, fmt.Errorf("unknown translation method: %s", name)
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface
-// takes byte input and converts it to a Method.
+// takes byte input and converts it to a Method, an error is
+// returned if the method is not registered.
 func (m *Method) UnmarshalJSON(b []byte) (err error) {
 	var s string
 	if err = json.Unmarshal(b, &s); err != nil {
 		return err
 	}
 
-	for k, v := range methodMappings {
-		if strings.EqualFold(s, v) {
-			*m = k
-			break
-		}
-	}
-
+	*m, err = ParseMethod(s)
 	return
 }
 
@@ -61,7 +192,7 @@ type request struct {
 type responseContents struct {
 	Translated  string `json:"translated"`  // Translated is the translated output.
 	Text        string `json:"text"`        // Text is the original input text
-	Translation Method `json:"translation"` // Translation is the translation method.
+	Translation string `json:"translation"` // Translation is the name of the translation method.
 }
 
 // responseSuccessData the data from the result which
@@ -1,9 +1,12 @@
 package translation
 
 import (
+	"encoding/json"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMethod_MarshalJSON(t *testing.T) {
@@ -159,7 +162,7 @@ func TestMethod_UnmarshalJSON(t *testing.T) {
 			Input: `"invalid"`,
 			Expected: func(t *testing.T, out Method, err error) {
 				assert.Equal(t, Method(0), out)
-				assert.NoError(t, err)
+				assert.EqualError(t, err, "unknown translation method: invalid")
 			},
 		},
 	}
@@ -172,3 +175,85 @@ func TestMethod_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMethod(t *testing.T) {
+	m, err := ParseMethod("Yoda")
+	assert.NoError(t, err)
+	assert.Equal(t, Yoda, m)
+
+	m, err = ParseMethod("shakespeare")
+	assert.NoError(t, err)
+	assert.Equal(t, Shakespeare, m)
+
+	// the name is normalised in the same way as when it is registered.
+	m, err = ParseMethod(" Shakespeare\n")
+	assert.NoError(t, err)
+	assert.Equal(t, Shakespeare, m)
+
+	_, err = ParseMethod("pirate")
+	assert.EqualError(t, err, "unknown translation method: pirate")
+}
+
+func TestRegister(t *testing.T) {
+	pirate, err := Register("Pirate")
+	require.NoError(t, err)
+	assert.Equal(t, "pirate", pirate.Name())
+
+	// registering a method again returns the existing method.
+	again, err := Register("pirate")
+	require.NoError(t, err)
+	assert.Equal(t, pirate, again)
+
+	// yoda is registered by default.
+	yoda, err := Register("yoda")
+	require.NoError(t, err)
+	assert.Equal(t, Yoda, yoda)
+
+	m, err := ParseMethod("PIRATE")
+	require.NoError(t, err)
+	assert.Equal(t, pirate, m)
+
+	var decoded Method
+	require.NoError(t, json.Unmarshal([]byte(`"pirate"`), &decoded))
+	assert.Equal(t, pirate, decoded)
+
+	for _, name := range []string{"", "pirate.json", "../yoda", "-minion"} {
+		_, err := Register(name)
+		assert.Error(t, err, name)
+	}
+
+	assert.Error(t, RegisterAll("minion", "not valid"))
+	_, err = ParseMethod("minion")
+	assert.NoError(t, err)
+}
+
+func TestMethods(t *testing.T) {
+	require.NoError(t, RegisterAll("klingon"))
+
+	methods := Methods()
+	names := make([]string, 0, len(methods))
+	for _, m := range methods {
+		names = append(names, m.Name())
+	}
+
+	assert.Contains(t, names, "klingon")
+	assert.True(t, sort.StringsAreSorted(names))
+}
+
+func TestRegistry(t *testing.T) {
+	require.NoError(t, RegisterAll("gungan"))
+
+	m, err := Registered().ParseMethod("Gungan")
+	require.NoError(t, err)
+	assert.Contains(t, Registered().Methods(), m)
+
+	r := NewRegistry(Yoda, Shakespeare, Method(-1))
+	assert.Equal(t, []Method{Shakespeare, Yoda}, r.Methods())
+
+	m, err = r.ParseMethod("YODA")
+	require.NoError(t, err)
+	assert.Equal(t, Yoda, m)
+
+	_, err = r.ParseMethod("gungan")
+	assert.EqualError(t, err, "unknown translation method: gungan")
+}